@@ -0,0 +1,164 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	. "github.com/donnie4w/gdao/base"
+	"github.com/donnie4w/gdao/gdaoCache"
+	"reflect"
+)
+
+// Count returns the number of rows of the query, or the number of groups when the query has a group by clause.
+// The order by and limit of the query are not used.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	n, err := hs.Where(hs.Age.GT(18)).Count()
+func (t *Table[T]) Count() (int64, error) {
+	sqlstr, args := t.countSql()
+	rows, err := aggregate[int64](t, "[COUNT]", sqlstr, args)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	return rows[0], nil
+}
+
+// Exists reports whether the query selects at least one row
+func (t *Table[T]) Exists() (bool, error) {
+	g := t.getDB(true)
+	if g == nil {
+		return false, errInit
+	}
+	sqlstr, args, err := t.existsSql(GetDialect(g.GetDBType()))
+	if err != nil {
+		return false, err
+	}
+	rows, err := aggregate[int64](t, "[EXISTS]", sqlstr, args)
+	return len(rows) > 0, err
+}
+
+// existsSql returns the select of the first row of the query, without order by, and its arguments
+func (t *Table[T]) existsSql(d Dialect) (string, []any, error) {
+	clause, err := d.Limit(1)
+	if err != nil {
+		return "", nil, err
+	}
+	where, _ := t.whereClause()
+	sqlstr := t.commentline + " select " + clause.Top + "1 from " + t.fromSql() + where + t.groupSql + t.havingSql + clause.Sql
+	return sqlstr, append(t.queryArgs(), clause.Args...), nil
+}
+
+// Sum returns the sum of the column over the rows of the query, 0 if there is none
+func (t *Table[T]) Sum(column Column[T]) (float64, error) {
+	return Aggregate[float64](t, &Func[T]{FieldName: "sum(" + t.columnName(column.Name()) + ")"})
+}
+
+// Avg returns the average of the column over the rows of the query, 0 if there is none
+func (t *Table[T]) Avg(column Column[T]) (float64, error) {
+	return Aggregate[float64](t, &Func[T]{FieldName: "avg(" + t.columnName(column.Name()) + ")"})
+}
+
+// Max returns the greatest value of the column over the rows of the query, as returned by the driver,
+// or nil if there is none. Use Aggregate to get a typed value.
+func (t *Table[T]) Max(column Column[T]) (any, error) {
+	return Aggregate[any](t, &Func[T]{FieldName: "max(" + t.columnName(column.Name()) + ")"})
+}
+
+// Min returns the least value of the column over the rows of the query, as returned by the driver,
+// or nil if there is none. Use Aggregate to get a typed value.
+func (t *Table[T]) Min(column Column[T]) (any, error) {
+	return Aggregate[any](t, &Func[T]{FieldName: "min(" + t.columnName(column.Name()) + ")"})
+}
+
+// Aggregate executes the query selecting the aggregate function fn and returns its value converted to R,
+// or the zero value of R if the query selects no row. With a group by clause the value of the
+// first group is returned, see Aggregates. The order by and limit of the query are only used with a group by clause.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.Where(hs.Age.GT(18))
+//	maxId, err := gdao.Aggregate[int64](&hs.Table, hs.Id.Max())
+//	last, err := gdao.Aggregate[time.Time](&hs.Table, hs.Createtime.Max())
+func Aggregate[R any, T any](t *Table[T], fn Column[T]) (r R, err error) {
+	rows, err := Aggregates[R](t, fn)
+	if err == nil && len(rows) > 0 {
+		r = rows[0]
+	}
+	return
+}
+
+// Aggregates executes the query selecting the aggregate function fn and returns its value
+// converted to R for every row, that is for every group when the query has a group by clause.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.GroupBy(hs.Age).OrderBy(hs.Age.Asc())
+//	counts, err := gdao.Aggregates[int64](&hs.Table, hs.Id.Count())
+func Aggregates[R any, T any](t *Table[T], fn Column[T]) ([]R, error) {
+	column := t.columnName(fn.Name())
+	if t.groupSql != "" {
+		g := t.getDB(true)
+		if g == nil {
+			return nil, errInit
+		}
+		sqlstr, args, err := t.selectSql(g, []string{column})
+		if err != nil {
+			return nil, err
+		}
+		return aggregate[R](t, "[AGGREGATE]", sqlstr, args)
+	}
+	where, _ := t.whereClause()
+	sqlstr := t.commentline + " select " + column + " from " + t.fromSql() + where + t.havingSql
+	return aggregate[R](t, "[AGGREGATE]", sqlstr, t.queryArgs())
+}
+
+// aggregate executes the query and returns the first column of every row converted to R.
+// The result is cached like the rows of Selects.
+func aggregate[R any, T any](t *Table[T], tag string, sqlstr string, args []any) (_r []R, err error) {
+	g := t.getDB(true)
+	if g == nil {
+		return nil, errInit
+	}
+	if Logger.IsVaild {
+		Logger.Debug(tag+"["+sqlstr+"]", args)
+	}
+	classname := t.getClassname()
+	domain, iscache := t.useCache()
+	var condition *gdaoCache.Condition
+	if iscache {
+		condition = gdaoCache.NewCondition("[]"+reflect.TypeFor[R]().String(), sqlstr, args...)
+		if result := gdaoCache.GetCacheContext(t.getContext(), domain, classname, condition); result != nil {
+			if Logger.IsVaild {
+				Logger.Debug("[GET CACHE]["+sqlstr+"]", args)
+			}
+			return result.([]R), nil
+		}
+	}
+	databeans := g.ExecuteQueryBeansContext(t.getContext(), sqlstr, args...)
+	if err = databeans.GetError(); err != nil {
+		return nil, err
+	}
+	_r = make([]R, 0, databeans.Len())
+	for _, bean := range databeans.Beans {
+		var r R
+		if v := bean.ValueByIndex(1); v != nil {
+			ScanValue(reflect.ValueOf(&r).Elem(), v)
+		}
+		_r = append(_r, r)
+	}
+	if iscache {
+		gdaoCache.SetCache(domain, classname, condition, _r)
+		if Logger.IsVaild {
+			Logger.Debug("[SET CACHE]["+sqlstr+"]", args)
+		}
+	}
+	return
+}
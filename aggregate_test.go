@@ -0,0 +1,51 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	. "github.com/donnie4w/gdao/base"
+	"testing"
+)
+
+func Test_existsSql(t *testing.T) {
+	tests := []struct {
+		dbtype DBType
+		sql    string
+		args   []any
+	}{
+		{MYSQL, " select 1 from hstest where age>? LIMIT ? ", []any{18, int64(1)}},
+		{POSTGRESQL, " select 1 from hstest where age>? LIMIT ? OFFSET 0 ", []any{18, int64(1)}},
+		{SQLITE, " select 1 from hstest where age>? LIMIT ? ", []any{18, int64(1)}},
+		{ORACLE, " select 1 from hstest where age>? FETCH FIRST ? ROWS ONLY ", []any{18, int64(1)}},
+		{SQLSERVER, " select top 1 1 from hstest where age>?", []any{18}},
+		{DB2, " select 1 from hstest where age>? FETCH FIRST ? ROWS ONLY ", []any{18, int64(1)}},
+		{INFORMIX, " select 1 from hstest where age>? FETCH FIRST ? ROWS ONLY ", []any{18, int64(1)}},
+		{SYBASE, " select top 1 1 from hstest where age>?", []any{18}},
+		{FIREBIRD, " select 1 from hstest where age>? ROWS ? ", []any{18, int64(1)}},
+	}
+	for _, tt := range tests {
+		hs := newHstest()
+		hs.Where(hs.AGE.GT(18))
+		sqlstr, args, err := hs.existsSql(GetDialect(tt.dbtype))
+		if err != nil {
+			t.Fatal(tt.dbtype, err)
+		}
+		checkSql(t, sqlstr, args, tt.sql, tt.args...)
+	}
+}
+
+func Test_Exists(t *testing.T) {
+	d := useTestDB(t, SQLSERVER, []string{"1"}, []driver.Value{int64(1)})
+	hs := newHstest()
+	ok, err := hs.Where(hs.ID.EQ(1)).Exists()
+	if err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+	checkStatements(t, d, " select top 1 1 from hstest where id=@p1[1]")
+}
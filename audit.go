@@ -0,0 +1,173 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"context"
+	"github.com/donnie4w/gdao/gdaoStruct"
+	"github.com/donnie4w/gdao/util"
+	"github.com/donnie4w/gofer/hashmap"
+	"maps"
+	"time"
+)
+
+// Audit is the audit columns of a table, filled by Insert, Update and AddBatch when they are not set on the entity.
+// A column with an empty name is not filled.
+type Audit struct {
+	// CreatedAt is the column set to the current time by Insert and AddBatch
+	CreatedAt string
+
+	// UpdatedAt is the column set to the current time by Insert, Update and AddBatch
+	UpdatedAt string
+
+	// CreatedBy is the column set to the actor by Insert and AddBatch
+	CreatedBy string
+
+	// UpdatedBy is the column set to the actor by Insert, Update and AddBatch
+	UpdatedBy string
+
+	// Now returns the current time, time.Now if nil
+	Now func() time.Time
+
+	// Actor returns the actor of the context of the statement and whether there is one,
+	// ActorFrom if nil. The by columns are not filled when there is no actor.
+	Actor func(ctx context.Context) (any, bool)
+}
+
+// NewAudit returns the Audit of the columns created_at, updated_at, created_by and updated_by
+func NewAudit() *Audit {
+	return &Audit{CreatedAt: "created_at", UpdatedAt: "updated_at", CreatedBy: "created_by", UpdatedBy: "updated_by"}
+}
+
+func (a *Audit) now() time.Time {
+	if a.Now != nil {
+		return a.Now()
+	}
+	return time.Now()
+}
+
+func (a *Audit) actor(ctx context.Context) (any, bool) {
+	if a.Actor != nil {
+		return a.Actor(ctx)
+	}
+	return ActorFrom(ctx)
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx holding the actor, such as a user id or name, written in the
+// created_by and updated_by columns of the statements executed with the context, see Audit.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.WithContext(gdao.WithActor(r.Context(), "donnie"))
+//	hs.SetName("hello")
+//	hs.Insert()
+func WithActor(ctx context.Context, actor any) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor held by ctx, set by WithActor
+func ActorFrom(ctx context.Context) (any, bool) {
+	actor := ctx.Value(actorKey{})
+	return actor, actor != nil
+}
+
+var audits = hashmap.NewMapL[string, *Audit]()
+
+// BindAudit sets the audit columns of the tables.
+//
+// Example:
+//
+//	gdao.BindAudit(gdao.NewAudit(), "users", "orders")
+func BindAudit(audit *Audit, tableNames ...string) {
+	for _, tableName := range tableNames {
+		audits.Put(tableName, audit)
+	}
+}
+
+// BindAuditWithClass sets the audit columns of the standardized entity class generated by gdao.
+//
+// Example:
+//
+//	gdao.BindAuditWithClass[dao.Hstest](&gdao.Audit{CreatedAt: "createtime", UpdatedAt: "updatetime"})
+func BindAuditWithClass[T gdaoStruct.TableClass](audit *Audit) {
+	audits.Put(util.Classname[T](), audit)
+}
+
+func UnbindAudit(tableNames ...string) {
+	for _, tableName := range tableNames {
+		audits.Del(tableName)
+	}
+}
+
+func UnbindAuditWithClass[T gdaoStruct.TableClass]() {
+	audits.Del(util.Classname[T]())
+}
+
+// UseAudit sets the audit columns of the table, overriding BindAudit and BindAuditWithClass
+func (t *Table[T]) UseAudit(audit *Audit) {
+	t.audit = audit
+}
+
+// getAudit returns the audit columns of the table, nil if there are none
+func (t *Table[T]) getAudit() *Audit {
+	if t.audit != nil {
+		return t.audit
+	}
+	if audit, ok := bound(audits, t.getClassname(), t.tableName); ok {
+		return audit
+	}
+	return nil
+}
+
+// auditValues returns the values set on the entity with the audit columns of an insert or an update
+// that are not set on it. An insert also has the values loaded from the row of the entity.
+// The values of the entity are not modified.
+func (t *Table[T]) auditValues(insert bool) map[string]any {
+	entity := t.modifymap
+	if insert {
+		entity = t.values()
+	}
+	audit := t.getAudit()
+	if audit == nil {
+		return entity
+	}
+	values := make(map[string]any, len(entity)+4)
+	maps.Copy(values, entity)
+	put := func(column string, value any) {
+		if _, ok := values[column]; column != "" && !ok {
+			values[column] = value
+		}
+	}
+	now := audit.now()
+	actor, hasActor := audit.actor(t.getContext())
+	if insert {
+		put(audit.CreatedAt, now)
+		if hasActor {
+			put(audit.CreatedBy, actor)
+		}
+	}
+	put(audit.UpdatedAt, now)
+	if hasActor {
+		put(audit.UpdatedBy, actor)
+	}
+	return values
+}
+
+// bound returns the value bound to the entity class, or else to the table name
+func bound[V any](m *hashmap.MapL[string, V], classname, tableName string) (v V, ok bool) {
+	if m.Len() == 0 {
+		return
+	}
+	if v, ok = m.Get(classname); ok {
+		return
+	}
+	return m.Get(tableName)
+}
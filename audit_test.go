@@ -0,0 +1,73 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_auditValues(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	hs := newHstest()
+	hs.SetName("a")
+	if v := hs.auditValues(true); !reflect.DeepEqual(v, map[string]any{"name": "a"}) {
+		t.Fatal(v)
+	}
+
+	audit := NewAudit()
+	audit.Now = func() time.Time { return now }
+	hs.UseAudit(audit)
+	if v := hs.auditValues(true); !reflect.DeepEqual(v, map[string]any{"name": "a", "created_at": now, "updated_at": now}) {
+		t.Fatal("no actor", v)
+	}
+
+	hs.WithContext(WithActor(context.Background(), "donnie"))
+	want := map[string]any{"name": "a", "created_at": now, "updated_at": now, "created_by": "donnie", "updated_by": "donnie"}
+	if v := hs.auditValues(true); !reflect.DeepEqual(v, want) {
+		t.Fatal("insert", v)
+	}
+	if v := hs.auditValues(false); !reflect.DeepEqual(v, map[string]any{"name": "a", "updated_at": now, "updated_by": "donnie"}) {
+		t.Fatal("update", v)
+	}
+	if len(hs.modifymap) != 1 {
+		t.Fatal("the columns set on the entity are modified", hs.modifymap)
+	}
+
+	audit.CreatedBy, audit.UpdatedBy = "", ""
+	audit.Actor = func(context.Context) (any, bool) { return 7, true }
+	hs.Put0("updated_at", "set")
+	if v := hs.auditValues(false); !reflect.DeepEqual(v, map[string]any{"name": "a", "updated_at": "set"}) {
+		t.Fatal("the column set on the entity", v)
+	}
+}
+
+func Test_Audit(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	d := useTestDB(t, MYSQL, nil)
+	BindAudit(&Audit{UpdatedAt: "updated_at", CreatedBy: "created_by", Now: func() time.Time { return now }}, "hstest")
+	t.Cleanup(func() { UnbindAudit("hstest") })
+
+	hs := newHstest()
+	hs.Where(hs.ID.EQ(1))
+	if _, err := hs.Update(); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, fmt.Sprint("update hstest set updated_at=? where id=?", []any{now, 1}))
+
+	hs = newHstest()
+	hs.UseAudit(&Audit{CreatedBy: "created_by"})
+	hs.WithContext(WithActor(context.Background(), "donnie"))
+	if _, err := hs.Insert(); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, "insert  into hstest(created_by )values(?)[donnie]")
+}
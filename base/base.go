@@ -8,9 +8,11 @@
 package base
 
 import (
+	"context"
 	"database/sql"
 	"github.com/donnie4w/gofer/base58"
 	"github.com/donnie4w/gofer/uuid"
+	"iter"
 )
 
 type TableBase interface {
@@ -20,6 +22,9 @@ type TableBase interface {
 type DBhandle interface {
 	GetTransaction() (r Transaction, err error)
 
+	// GetTransactionContext begins a transaction bound to ctx; the transaction is rolled back if ctx is done before it is committed
+	GetTransactionContext(ctx context.Context, opts *sql.TxOptions) (r Transaction, err error)
+
 	ExecuteQueryBean(sql string, args ...any) *DataBean
 
 	ExecuteQueryBeans(sql string, args ...any) *DataBeans
@@ -28,6 +33,18 @@ type DBhandle interface {
 
 	ExecuteBatch(sql string, args [][]any) (r []sql.Result, err error)
 
+	ExecuteQueryBeanContext(ctx context.Context, sql string, args ...any) *DataBean
+
+	ExecuteQueryBeansContext(ctx context.Context, sql string, args ...any) *DataBeans
+
+	ExecuteUpdateContext(ctx context.Context, sql string, args ...any) (sql.Result, error)
+
+	ExecuteBatchContext(ctx context.Context, sql string, args [][]any) (r []sql.Result, err error)
+
+	// ExecuteQueryIter returns an iterator that streams the result rows one at a time,
+	// keeping the underlying *sql.Rows open until the iteration ends or the loop breaks
+	ExecuteQueryIter(ctx context.Context, sql string, args ...any) iter.Seq2[*DataBean, error]
+
 	GetDBType() DBType
 
 	GetDB() *sql.DB
@@ -45,6 +62,13 @@ type Scanner interface {
 	ToGdao()
 }
 
+// Loader is implemented by the standardized entity classes through their Table. Loaded is called once the
+// values of a row are scanned into the entity, so that the values loaded are not taken for values set
+// by the application: Update and UpdateByKey only write the columns set after the entity is loaded.
+type Loader interface {
+	Loaded()
+}
+
 var MapperPre = string(base58.EncodeForInt64(uint64(uuid.NewUUID().Int64())))
 
 type In struct {
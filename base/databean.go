@@ -228,10 +228,13 @@ func (g *DataBean) scan(v any, free bool) (err error) {
 			for name, fieldBean := range g.fieldMapName {
 				scanner.Scan(name, fieldBean.Value())
 			}
+			if loader, ok := v.(Loader); ok {
+				loader.Loaded()
+			}
 			if free {
 				g.free()
 			}
-			return nil
+			return afterFind(v)
 			//val := reflect.ValueOf(scanner).Elem()
 			//return val.Addr().Interface().(*T), nil
 		}
@@ -300,7 +303,7 @@ func (g *DataBean) scan(v any, free bool) (err error) {
 		if free {
 			g.free()
 		}
-		return
+		return afterFind(v)
 	}
 	return fmt.Errorf("DataBean is nil")
 }
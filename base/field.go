@@ -16,58 +16,97 @@ type Field[T any] struct {
 	FieldName string
 }
 
+// FieldBase is implemented by the fields of any standardized entity class
+type FieldBase interface {
+	Name() string
+}
+
+// Subquery is a select statement used in the conditions of another query.
+// The Table of every standardized entity class implements it.
+type Subquery interface {
+	// SubquerySql returns the select statement with ? placeholders and its arguments in order
+	SubquerySql() (sql string, args []any)
+}
+
+// WhereExists returns the condition exists (select ...). See gdao.Exists.
+func WhereExists[T any](q Subquery) *Where[T] {
+	sql, args := q.SubquerySql()
+	return &Where[T]{WhereSql: "exists (" + sql + ")", Values: args}
+}
+
+// WhereNotExists returns the condition not exists (select ...). See gdao.NotExists.
+func WhereNotExists[T any](q Subquery) *Where[T] {
+	sql, args := q.SubquerySql()
+	return &Where[T]{WhereSql: "not exists (" + sql + ")", Values: args}
+}
+
 func (f *Field[T]) Name() string {
 	return f.FieldName
 }
 
 // EQ : =
 func (f *Field[T]) EQ(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + "=?", arg, nil}
+	return &Where[T]{WhereSql: f.FieldName + "=?", Value: arg}
 }
 
 // NEQ : <>
 func (f *Field[T]) NEQ(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + "<>?", arg, nil}
+	return &Where[T]{WhereSql: f.FieldName + "<>?", Value: arg}
+}
+
+// EqField : = 'otherField', compares two columns, typically of joined tables
+func (f *Field[T]) EqField(other FieldBase) *Where[T] {
+	return &Where[T]{WhereSql: f.FieldName + "=" + other.Name()}
+}
+
+// IsNull : is null
+func (f *Field[T]) IsNull() *Where[T] {
+	return &Where[T]{WhereSql: f.FieldName + " is null"}
+}
+
+// IsNotNull : is not null
+func (f *Field[T]) IsNotNull() *Where[T] {
+	return &Where[T]{WhereSql: f.FieldName + " is not null"}
 }
 
 // LT : <
 func (f *Field[T]) LT(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + "<?", arg, nil}
+	return &Where[T]{WhereSql: f.FieldName + "<?", Value: arg}
 }
 
 // LE : <=
 func (f *Field[T]) LE(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + "<=?", arg, nil}
+	return &Where[T]{WhereSql: f.FieldName + "<=?", Value: arg}
 }
 
 // GT : >
 func (f *Field[T]) GT(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + ">?", arg, nil}
+	return &Where[T]{WhereSql: f.FieldName + ">?", Value: arg}
 }
 
 // GE : >=
 func (f *Field[T]) GE(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + ">=?", arg, nil}
+	return &Where[T]{WhereSql: f.FieldName + ">=?", Value: arg}
 }
 
 // LIKE : like %?%
 func (f *Field[T]) LIKE(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + " like ?", fmt.Sprint("%", arg, "%"), nil}
+	return &Where[T]{WhereSql: f.FieldName + " like ?", Value: fmt.Sprint("%", arg, "%")}
 }
 
 // RLIKE : like %?
 func (f *Field[T]) RLIKE(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + " like ?", fmt.Sprint("%", arg), nil}
+	return &Where[T]{WhereSql: f.FieldName + " like ?", Value: fmt.Sprint("%", arg)}
 }
 
 // LLIKE : like ?%
 func (f *Field[T]) LLIKE(arg any) *Where[T] {
-	return &Where[T]{f.FieldName + " like ?", fmt.Sprint(arg, "%"), nil}
+	return &Where[T]{WhereSql: f.FieldName + " like ?", Value: fmt.Sprint(arg, "%")}
 }
 
 // Between : between ? and ?"
 func (f *Field[T]) Between(from, to any) *Where[T] {
-	return &Where[T]{f.FieldName + " between ? and ?", nil, []any{from, to}}
+	return &Where[T]{WhereSql: f.FieldName + " between ? and ?", Values: []any{from, to}}
 }
 
 // IN : in()
@@ -79,7 +118,7 @@ func (f *Field[T]) IN(args ...any) *Where[T] {
 			buider.WriteRune(',')
 		}
 	}
-	return &Where[T]{f.FieldName + " in (" + buider.String() + ")", nil, args}
+	return &Where[T]{WhereSql: f.FieldName + " in (" + buider.String() + ")", Values: args}
 }
 
 // NOTIN : not in()
@@ -91,7 +130,60 @@ func (f *Field[T]) NOTIN(args ...any) *Where[T] {
 			buider.WriteRune(',')
 		}
 	}
-	return &Where[T]{f.FieldName + " not in (" + buider.String() + ")", nil, args}
+	return &Where[T]{WhereSql: f.FieldName + " not in (" + buider.String() + ")", Values: args}
+}
+
+// InSubquery : in (select ...)
+// The sql and arguments of the subquery are taken when InSubquery is called.
+//
+// Example:
+//
+//	order := dao.NewOrders()
+//	order.Where(order.Amount.GT(100))
+//	hs := dao.NewHstest()
+//	hs.Where(hs.Id.InSubquery(order.Subquery(order.UserId)))
+func (f *Field[T]) InSubquery(q Subquery) *Where[T] {
+	return f.subquery(" in ", q)
+}
+
+// NotInSubquery : not in (select ...)
+func (f *Field[T]) NotInSubquery(q Subquery) *Where[T] {
+	return f.subquery(" not in ", q)
+}
+
+// EQSubquery : = (select ...), the subquery must return a single value
+func (f *Field[T]) EQSubquery(q Subquery) *Where[T] {
+	return f.subquery("=", q)
+}
+
+// NEQSubquery : <> (select ...), the subquery must return a single value
+func (f *Field[T]) NEQSubquery(q Subquery) *Where[T] {
+	return f.subquery("<>", q)
+}
+
+// LTSubquery : < (select ...), the subquery must return a single value
+func (f *Field[T]) LTSubquery(q Subquery) *Where[T] {
+	return f.subquery("<", q)
+}
+
+// LESubquery : <= (select ...), the subquery must return a single value
+func (f *Field[T]) LESubquery(q Subquery) *Where[T] {
+	return f.subquery("<=", q)
+}
+
+// GTSubquery : > (select ...), the subquery must return a single value
+func (f *Field[T]) GTSubquery(q Subquery) *Where[T] {
+	return f.subquery(">", q)
+}
+
+// GESubquery : >= (select ...), the subquery must return a single value
+func (f *Field[T]) GESubquery(q Subquery) *Where[T] {
+	return f.subquery(">=", q)
+}
+
+func (f *Field[T]) subquery(op string, q Subquery) *Where[T] {
+	sql, args := q.SubquerySql()
+	return &Where[T]{WhereSql: f.FieldName + op + "(" + sql + ")", Values: args}
 }
 
 // Asc : order by 'fieldName' asc
@@ -0,0 +1,68 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package base
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testSubquery struct {
+	sql  string
+	args []any
+}
+
+func (s *testSubquery) SubquerySql() (string, []any) {
+	return s.sql, s.args
+}
+
+func Test_subquery(t *testing.T) {
+	id := &Field[struct{}]{"id"}
+	q := &testSubquery{"select user_id from orders where amount>? limit ?", []any{100, 10}}
+	w := id.EQ(1).And(id.InSubquery(q))
+	fmt.Println(w.WhereSql, w.Value, w.Values)
+	if w.WhereSql != "id=? and (id in (select user_id from orders where amount>? limit ?))" {
+		t.Fatal(w.WhereSql)
+	}
+	if len(w.Values) != 2 || w.Values[0] != 100 || w.Values[1] != 10 {
+		t.Fatal(w.Values)
+	}
+	if w = WhereNotExists[struct{}](q); w.WhereSql != "not exists (select user_id from orders where amount>? limit ?)" {
+		t.Fatal(w.WhereSql)
+	}
+	if w = id.GESubquery(q); w.WhereSql != "id>=(select user_id from orders where amount>? limit ?)" {
+		t.Fatal(w.WhereSql)
+	}
+}
+
+func Test_whereTree(t *testing.T) {
+	a, b, c, d := &Field[struct{}]{"a"}, &Field[struct{}]{"b"}, &Field[struct{}]{"c"}, &Field[struct{}]{"d"}
+	w := WhereJoin("or", WhereJoin("and", a.EQ(1), b.Between(2, 3)), WhereJoin("and", c.IN(4, 5), WhereNot(d.IsNull())))
+	if w.WhereSql != "(a=? and b between ? and ?) or (c in (?,?) and not (d is null))" {
+		t.Fatal(w.WhereSql)
+	}
+	if fmt.Sprint(w.Values) != "[1 2 3 4 5]" || w.Value != nil {
+		t.Fatal(w.Values)
+	}
+	w = WhereJoin("and", WhereJoin("or", a.EQ(1), b.IsNotNull()), c.EqField(d), WhereJoin("and", d.LT(2), a.GT(3)))
+	if w.WhereSql != "(a=? or b is not null) and c=d and d<? and a>?" || fmt.Sprint(w.Values) != "[1 2 3]" {
+		t.Fatal(w.WhereSql, w.Values)
+	}
+	w = a.EQ(1).Or(b.EQ(2)).And(c.EQ(3), d.EQ(4))
+	if w.WhereSql != "(a=? or (b=?)) and (c=? and d=?)" || fmt.Sprint(w.Value, w.Values) != "1 [2 3 4]" {
+		t.Fatal(w.WhereSql, w.Value, w.Values)
+	}
+	w = WhereJoin("or", a.Between(1, 2), WhereNot(WhereJoin("and", b.EQ(3), c.EQ(4))))
+	if w.WhereSql != "a between ? and ? or not (b=? and c=?)" || w.Op() != "or" {
+		t.Fatal(w.WhereSql, w.Op())
+	}
+	w = a.Between(1, 2).Or(b.EQ(3))
+	if w.WhereSql != "a between ? and ? or (b=?)" || fmt.Sprint(w.Values) != "[1 2 3]" {
+		t.Fatal(w.WhereSql, w.Values)
+	}
+}
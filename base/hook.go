@@ -0,0 +1,66 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package base
+
+// The lifecycle hooks of the entities. A standardized entity class implementing one of these interfaces,
+// with a pointer receiver, has the hook called by the Table statements of the entity.
+// The statement is not executed if a Before hook returns an error, and the error is returned by the statement.
+// An error returned by an After hook is returned by the statement, which has already been executed.
+//
+// Example:
+//
+//	func (u *Hstest) BeforeInsert() error {
+//	    if u.GetName() == "" {
+//	        return errors.New("name is required")
+//	    }
+//	    return nil
+//	}
+
+// BeforeInserter is called by Table.Insert before the row is inserted
+type BeforeInserter interface {
+	BeforeInsert() error
+}
+
+// AfterInserter is called by Table.Insert after the row is inserted
+type AfterInserter interface {
+	AfterInsert() error
+}
+
+// BeforeUpdater is called by Table.Update before the rows are updated
+type BeforeUpdater interface {
+	BeforeUpdate() error
+}
+
+// AfterUpdater is called by Table.Update after the rows are updated
+type AfterUpdater interface {
+	AfterUpdate() error
+}
+
+// BeforeDeleter is called by Table.Delete and Table.HardDelete before the rows are deleted
+type BeforeDeleter interface {
+	BeforeDelete() error
+}
+
+// AfterDeleter is called by Table.Delete and Table.HardDelete after the rows are deleted
+type AfterDeleter interface {
+	AfterDelete() error
+}
+
+// AfterFinder is called on every entity scanned from the rows of a query,
+// by the Select methods of Table, the gdaoMapper selects and DataBean.Scan
+type AfterFinder interface {
+	AfterFind() error
+}
+
+// afterFind calls the AfterFind hook of v if it implements AfterFinder
+func afterFind(v any) error {
+	if h, ok := v.(AfterFinder); ok {
+		return h.AfterFind()
+	}
+	return nil
+}
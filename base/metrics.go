@@ -0,0 +1,229 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package base
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The metrics of gdao, see gdao.PublishMetrics
+const (
+	// MetricQueries counts the statements per datasource and table
+	MetricQueries = "gdao_queries_total"
+	// MetricErrors counts the statements failing, per datasource and table
+	MetricErrors = "gdao_errors_total"
+	// MetricQueryDuration is the histogram of the time of the statements in seconds, per datasource and table
+	MetricQueryDuration = "gdao_query_duration_seconds"
+	// MetricStmtCacheHits, MetricStmtCacheMisses and MetricStmtCacheEvictions count the lookups and the evictions
+	// of the prepared statements cache per datasource
+	MetricStmtCacheHits      = "gdao_stmt_cache_hits_total"
+	MetricStmtCacheMisses    = "gdao_stmt_cache_misses_total"
+	MetricStmtCacheEvictions = "gdao_stmt_cache_evictions_total"
+	// MetricStmtCacheSize is the number of prepared statements cached per datasource
+	MetricStmtCacheSize = "gdao_stmt_cache_size"
+	// MetricCacheHits, MetricCacheMisses and MetricCacheEvictions count the lookups and the evictions
+	// of the gdaoCache per domain
+	MetricCacheHits      = "gdao_cache_hits_total"
+	MetricCacheMisses    = "gdao_cache_misses_total"
+	MetricCacheEvictions = "gdao_cache_evictions_total"
+	// MetricOpenTransactions is the number of transactions begun and not committed or rolled back, per datasource
+	MetricOpenTransactions = "gdao_open_transactions"
+)
+
+// The labels of the metrics
+const (
+	LabelDataSource = "datasource"
+	LabelTable      = "table"
+	LabelDomain     = "domain"
+)
+
+// HistogramBuckets are the upper bounds of the buckets of the histograms
+var HistogramBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Labels are the labels of a metric
+type Labels map[string]string
+
+// MetricsSink receives the metrics of gdao as they change, to forward them to a metrics library
+// such as Prometheus, see gdao.SetMetricsSink
+type MetricsSink interface {
+	// Add adds delta to the counter name
+	Add(name string, labels Labels, delta int64)
+
+	// Set sets the gauge name to value
+	Set(name string, labels Labels, value int64)
+
+	// Observe records value in the histogram name
+	Observe(name string, labels Labels, value float64)
+}
+
+type histogram struct {
+	mux     sync.Mutex
+	buckets []int64
+	count   int64
+	sum     float64
+}
+
+// HistogramSnapshot is the state of a histogram, Buckets holding the cumulative count of every upper bound
+type HistogramSnapshot struct {
+	Count   int64
+	Sum     float64
+	Buckets map[string]int64
+}
+
+var (
+	metricsOn   atomic.Bool
+	metricsSink atomic.Pointer[MetricsSink]
+	counters    sync.Map
+	gauges      sync.Map
+	histograms  sync.Map
+)
+
+// EnableMetrics turns the recording of the metrics on or off, it is off by default
+func EnableMetrics(on bool) {
+	metricsOn.Store(on)
+}
+
+// MetricsEnabled reports whether the metrics are recorded
+func MetricsEnabled() bool {
+	return metricsOn.Load()
+}
+
+// UseMetricsSink sets the sink receiving the metrics, nil removes it
+func UseMetricsSink(sink MetricsSink) {
+	if sink == nil {
+		metricsSink.Store(nil)
+	} else {
+		metricsSink.Store(&sink)
+	}
+}
+
+// metricKey returns the key of the metric with the labels, given as name and value pairs
+func metricKey(name string, labels []string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	var b strings.Builder
+	b.WriteString(name)
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i == 0 {
+			b.WriteByte('{')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i])
+		b.WriteString("=")
+		b.WriteString(strconv.Quote(labels[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func toLabels(labels []string) Labels {
+	m := make(Labels, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		m[labels[i]] = labels[i+1]
+	}
+	return m
+}
+
+func metric[T any](m *sync.Map, key string, create func() *T) *T {
+	if v, ok := m.Load(key); ok {
+		return v.(*T)
+	}
+	v, _ := m.LoadOrStore(key, create())
+	return v.(*T)
+}
+
+// AddCounter adds delta to the counter name with the labels, given as name and value pairs
+func AddCounter(name string, delta int64, labels ...string) {
+	if !metricsOn.Load() {
+		return
+	}
+	metric(&counters, metricKey(name, labels), func() *atomic.Int64 { return new(atomic.Int64) }).Add(delta)
+	if sink := metricsSink.Load(); sink != nil {
+		(*sink).Add(name, toLabels(labels), delta)
+	}
+}
+
+// AddGauge adds delta to the gauge name with the labels, given as name and value pairs.
+// The gauges are recorded when the metrics are off, so that they hold the right value when they are turned on.
+func AddGauge(name string, delta int64, labels ...string) {
+	v := metric(&gauges, metricKey(name, labels), func() *atomic.Int64 { return new(atomic.Int64) }).Add(delta)
+	if sink := metricsSink.Load(); sink != nil && metricsOn.Load() {
+		(*sink).Set(name, toLabels(labels), v)
+	}
+}
+
+// SetGauge sets the gauge name with the labels, given as name and value pairs
+func SetGauge(name string, value int64, labels ...string) {
+	if !metricsOn.Load() {
+		return
+	}
+	metric(&gauges, metricKey(name, labels), func() *atomic.Int64 { return new(atomic.Int64) }).Store(value)
+	if sink := metricsSink.Load(); sink != nil {
+		(*sink).Set(name, toLabels(labels), value)
+	}
+}
+
+// Observe records value in the histogram name with the labels, given as name and value pairs
+func Observe(name string, value float64, labels ...string) {
+	if !metricsOn.Load() {
+		return
+	}
+	h := metric(&histograms, metricKey(name, labels), func() *histogram {
+		return &histogram{buckets: make([]int64, len(HistogramBuckets))}
+	})
+	h.mux.Lock()
+	for i, bound := range HistogramBuckets {
+		if value <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+	h.mux.Unlock()
+	if sink := metricsSink.Load(); sink != nil {
+		(*sink).Observe(name, toLabels(labels), value)
+	}
+}
+
+// MetricsSnapshot returns the counters, the gauges and the histograms, keyed by their name and labels
+// such as gdao_queries_total{datasource="default",table="user"}
+func MetricsSnapshot() (counterValues, gaugeValues map[string]int64, histogramValues map[string]HistogramSnapshot) {
+	counterValues, gaugeValues, histogramValues = map[string]int64{}, map[string]int64{}, map[string]HistogramSnapshot{}
+	counters.Range(func(k, v any) bool {
+		counterValues[k.(string)] = v.(*atomic.Int64).Load()
+		return true
+	})
+	gauges.Range(func(k, v any) bool {
+		gaugeValues[k.(string)] = v.(*atomic.Int64).Load()
+		return true
+	})
+	histograms.Range(func(k, v any) bool {
+		h := v.(*histogram)
+		h.mux.Lock()
+		s := HistogramSnapshot{Count: h.count, Sum: h.sum, Buckets: make(map[string]int64, len(h.buckets)+1)}
+		for i, bound := range HistogramBuckets {
+			s.Buckets[strconv.FormatFloat(bound, 'g', -1, 64)] = h.buckets[i]
+		}
+		s.Buckets["+Inf"] = h.count
+		h.mux.Unlock()
+		histogramValues[k.(string)] = s
+		return true
+	})
+	return
+}
+
+// ResetMetrics resets the counters and the histograms, the gauges are kept
+func ResetMetrics() {
+	counters.Clear()
+	histograms.Clear()
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package base
+
+import (
+	"testing"
+)
+
+type testSink struct {
+	added, set int64
+	observed   float64
+}
+
+func (s *testSink) Add(name string, labels Labels, delta int64) {
+	s.added += delta
+}
+
+func (s *testSink) Set(name string, labels Labels, value int64) {
+	s.set = value
+}
+
+func (s *testSink) Observe(name string, labels Labels, value float64) {
+	s.observed += value
+}
+
+func Test_metrics(t *testing.T) {
+	AddCounter(MetricQueries, 1, LabelTable, "off")
+	AddGauge(MetricOpenTransactions, 1)
+	sink := &testSink{}
+	EnableMetrics(true)
+	UseMetricsSink(sink)
+	defer EnableMetrics(false)
+	defer UseMetricsSink(nil)
+	AddCounter(MetricQueries, 2, LabelDataSource, "default", LabelTable, "user")
+	AddCounter(MetricQueries, 1, LabelDataSource, "default", LabelTable, "user")
+	AddGauge(MetricOpenTransactions, 1)
+	Observe(MetricQueryDuration, 0.02, LabelTable, "user")
+	counters, gauges, histograms := MetricsSnapshot()
+	if n := counters[`gdao_queries_total{datasource="default",table="user"}`]; n != 3 || len(counters) != 1 {
+		t.Fatal(counters)
+	}
+	if gauges[MetricOpenTransactions] != 2 || sink.set != 2 {
+		t.Fatal(gauges, sink.set)
+	}
+	h := histograms[`gdao_query_duration_seconds{table="user"}`]
+	if h.Count != 1 || h.Buckets["0.01"] != 0 || h.Buckets["0.025"] != 1 || h.Buckets["+Inf"] != 1 {
+		t.Fatal(h)
+	}
+	if sink.added != 3 || sink.observed != 0.02 {
+		t.Fatal(sink)
+	}
+	ResetMetrics()
+	if counters, gauges, _ = MetricsSnapshot(); len(counters) != 0 || gauges[MetricOpenTransactions] != 2 {
+		t.Fatal(counters, gauges)
+	}
+}
@@ -7,7 +7,9 @@
 
 package base
 
-import "strings"
+import (
+	"strings"
+)
 
 type Column[T any] interface {
 	Name() string
@@ -27,6 +29,14 @@ type Where[T any] struct {
 	WhereSql string
 	Value    any
 	Values   []any
+	// op is the operator joining the conditions of a Where built by And or Or, empty for one condition
+	op string
+}
+
+// Op returns the operator and or or joining the conditions of the Where at its top level,
+// or an empty string if the Where is a single condition
+func (w *Where[T]) Op() string {
+	return w.op
 }
 
 type Having[T any] struct {
@@ -35,40 +45,70 @@ type Having[T any] struct {
 	Values    []any
 }
 
+// And returns w and the conditions joined with and, as w and (wheres[0] and wheres[1] ...).
+// w is changed to the returned condition.
+//
+// Example:
+//
+//	hs.Where(hs.Age.GE(18).And(hs.Name.LIKE("a"), hs.Id.LT(100)))
+//	// where age>=? and (name like ? and id<?)
 func (w *Where[T]) And(wheres ...*Where[T]) *Where[T] {
-	whereSqls := make([]string, 0, len(wheres))
-	for _, v := range wheres {
-		whereSqls = append(whereSqls, v.WhereSql)
-		if v.Value != nil {
-			w.Values = append(w.Values, v.Value)
-		}
-		if v.Values != nil {
-			for _, vv := range v.Values {
-				w.Values = append(w.Values, vv)
-			}
-		}
-	}
-	w.WhereSql = w.WhereSql + " and (" + strings.Join(whereSqls, " or ") + ")"
-	return w
+	return w.join("and", wheres)
 }
 
+// Or returns w or the conditions joined with or, as w or (wheres[0] or wheres[1] ...).
+// w is changed to the returned condition.
 func (w *Where[T]) Or(wheres ...*Where[T]) *Where[T] {
-	whereSqls := make([]string, 0, len(wheres))
-	for _, v := range wheres {
-		whereSqls = append(whereSqls, v.WhereSql)
-		if v.Value != nil {
-			w.Values = append(w.Values, v.Value)
-		}
-		if v.Values != nil {
-			for _, vv := range v.Values {
-				w.Values = append(w.Values, vv)
-			}
-		}
+	return w.join("or", wheres)
+}
+
+func (w *Where[T]) join(op string, wheres []*Where[T]) *Where[T] {
+	if len(wheres) == 0 {
+		return w
 	}
-	w.WhereSql = w.WhereSql + " or (" + strings.Join(whereSqls, " and ") + ")"
+	j := WhereJoin(op, wheres...)
+	w.WhereSql = group(op, w) + " " + op + " (" + j.WhereSql + ")"
+	w.Values = append(append([]any{}, w.Values...), j.Values...)
+	w.op = op
 	return w
 }
 
+// args returns the arguments of the condition in order
+func (w *Where[T]) args() []any {
+	if w.Value != nil {
+		return append([]any{w.Value}, w.Values...)
+	}
+	return w.Values
+}
+
+// WhereJoin returns the conditions joined with the operator and or or, in parentheses if they hold
+// another operator, such as a and b and (c or d). See gdao.And and gdao.Or.
+func WhereJoin[T any](op string, wheres ...*Where[T]) *Where[T] {
+	if len(wheres) == 1 {
+		return &Where[T]{WhereSql: wheres[0].WhereSql, Values: wheres[0].args(), op: wheres[0].op}
+	}
+	sqls := make([]string, 0, len(wheres))
+	args := make([]any, 0, len(wheres))
+	for _, w := range wheres {
+		sqls = append(sqls, group(op, w))
+		args = append(args, w.args()...)
+	}
+	return &Where[T]{WhereSql: strings.Join(sqls, " "+op+" "), Values: args, op: op}
+}
+
+// WhereNot returns the negation of the condition, not (w). See gdao.Not.
+func WhereNot[T any](w *Where[T]) *Where[T] {
+	return &Where[T]{WhereSql: "not (" + w.WhereSql + ")", Values: w.args()}
+}
+
+// group returns the condition in parentheses if it was joined with an operator other than op
+func group[T any](op string, w *Where[T]) string {
+	if w.op != "" && w.op != op {
+		return "(" + w.WhereSql + ")"
+	}
+	return w.WhereSql
+}
+
 type Func[T any] struct {
 	FieldName  string
 	FieldValue any
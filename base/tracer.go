@@ -0,0 +1,137 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package base
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The names of the spans started by gdao
+const (
+	// SpanStatement wraps the execution of a statement, with the interceptors
+	SpanStatement = "gdao.statement"
+	// SpanTxBegin, SpanTxCommit and SpanTxRollback wrap the begin, the commit and the rollback of a transaction
+	SpanTxBegin    = "gdao.tx.begin"
+	SpanTxCommit   = "gdao.tx.commit"
+	SpanTxRollback = "gdao.tx.rollback"
+	// SpanMapperResolve wraps the resolution of a mapper id into its statement and arguments
+	SpanMapperResolve = "gdao.mapper.resolve"
+	// SpanCacheGet wraps the lookup of a result in the gdao cache
+	SpanCacheGet = "gdao.cache.get"
+)
+
+// The attributes of the spans started by gdao
+const (
+	AttrDBSystem     = "db.system"
+	AttrDBStatement  = "db.statement"
+	AttrDBOperation  = "db.operation"
+	AttrDataSource   = "gdao.datasource"
+	AttrRowsAffected = "db.rows_affected"
+	AttrRowsReturned = "db.rows_returned"
+	AttrMapperId     = "gdao.mapper.id"
+	AttrCacheDomain  = "gdao.cache.domain"
+	AttrCacheHit     = "gdao.cache.hit"
+)
+
+// Attrs are the attributes of a span
+type Attrs map[string]any
+
+// Tracer starts the spans of gdao, see SetTracer. It is implemented by an adapter of the tracing library
+// of the application, such as OpenTelemetry, or by SpanRecorder.
+type Tracer interface {
+	// StartSpan starts the span name, child of the span of ctx, and returns the context holding the span
+	// and the function ending it with the error of the operation. gdao may add attributes to attrs,
+	// such as the rows affected, before calling end: the tracer reads them when the span ends.
+	StartSpan(ctx context.Context, name string, attrs Attrs) (context.Context, func(err error))
+}
+
+var tracer atomic.Pointer[Tracer]
+
+// UseTracer sets the Tracer of gdao, nil removes it, see gdao.SetTracer
+func UseTracer(t Tracer) {
+	if t == nil {
+		tracer.Store(nil)
+	} else {
+		tracer.Store(&t)
+	}
+}
+
+// Tracing reports whether a Tracer is set
+func Tracing() bool {
+	return tracer.Load() != nil
+}
+
+func endSpan(error) {}
+
+// StartSpan starts a span with the Tracer, it returns ctx and a function doing nothing if there is no Tracer
+func StartSpan(ctx context.Context, name string, attrs Attrs) (context.Context, func(err error)) {
+	t := tracer.Load()
+	if t == nil {
+		return ctx, endSpan
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return (*t).StartSpan(ctx, name, attrs)
+}
+
+// RecordedSpan is a span recorded by SpanRecorder
+type RecordedSpan struct {
+	Name   string
+	Attrs  Attrs
+	Err    error
+	Start  time.Time
+	End    time.Time
+	Parent *RecordedSpan
+}
+
+type spanKey struct{}
+
+// SpanRecorder is a Tracer keeping the ended spans in memory, for the tests.
+//
+// Example:
+//
+//	recorder := &base.SpanRecorder{}
+//	gdao.SetTracer(recorder)
+//	hs.Selects()
+//	for _, span := range recorder.Spans() {
+//	    fmt.Println(span.Name, span.Attrs[base.AttrDBStatement], span.Err)
+//	}
+type SpanRecorder struct {
+	mux   sync.Mutex
+	spans []*RecordedSpan
+}
+
+func (r *SpanRecorder) StartSpan(ctx context.Context, name string, attrs Attrs) (context.Context, func(err error)) {
+	span := &RecordedSpan{Name: name, Start: time.Now()}
+	span.Parent, _ = ctx.Value(spanKey{}).(*RecordedSpan)
+	return context.WithValue(ctx, spanKey{}, span), func(err error) {
+		span.Attrs, span.Err, span.End = maps.Clone(attrs), err, time.Now()
+		r.mux.Lock()
+		defer r.mux.Unlock()
+		r.spans = append(r.spans, span)
+	}
+}
+
+// Spans returns the ended spans, in the order they ended
+func (r *SpanRecorder) Spans() []*RecordedSpan {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]*RecordedSpan{}, r.spans...)
+}
+
+// Reset removes the recorded spans
+func (r *SpanRecorder) Reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.spans = nil
+}
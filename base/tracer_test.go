@@ -0,0 +1,42 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package base
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_spanRecorder(t *testing.T) {
+	if _, end := StartSpan(context.Background(), SpanStatement, nil); end == nil {
+		t.Fatal("nil end without a tracer")
+	}
+	recorder := &SpanRecorder{}
+	UseTracer(recorder)
+	defer UseTracer(nil)
+	ctx, end := StartSpan(context.Background(), SpanTxBegin, nil)
+	attrs := Attrs{AttrDBStatement: "update t set a=?"}
+	_, endStmt := StartSpan(ctx, SpanStatement, attrs)
+	attrs[AttrRowsAffected] = int64(2)
+	endStmt(errors.New("failed"))
+	end(nil)
+	spans := recorder.Spans()
+	if len(spans) != 2 || spans[0].Name != SpanStatement || spans[1].Name != SpanTxBegin {
+		t.Fatal(spans)
+	}
+	if spans[0].Parent != spans[1] || spans[1].Parent != nil {
+		t.Fatal("wrong parent", spans[0].Parent)
+	}
+	if spans[0].Attrs[AttrRowsAffected] != int64(2) || spans[0].Err == nil {
+		t.Fatal(spans[0].Attrs, spans[0].Err)
+	}
+	if recorder.Reset(); len(recorder.Spans()) != 0 {
+		t.Fatal("spans not reset")
+	}
+}
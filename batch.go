@@ -0,0 +1,146 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"context"
+	"database/sql"
+	. "github.com/donnie4w/gdao/base"
+	"github.com/donnie4w/gdao/util"
+	"strings"
+	"unicode"
+)
+
+var multiRowBatch bool
+
+// MultiRowBatch sets whether the insert batches of ExecuteBatch and Table.ExecBatch are
+// rewritten into multi-row INSERT ... VALUES (...),(...) statements (INSERT ALL for Oracle)
+// instead of executing the prepared statement once per row. The rows are sent in chunks
+// that respect the limits of the Dialect, and one sql.Result is returned per chunk. The chunks
+// are executed in a transaction when the batch is not executed in one already.
+// Statements that are not a single-row insert, or databases without multi-row inserts,
+// still execute once per row. The default is false.
+func MultiRowBatch(on bool) {
+	multiRowBatch = on
+}
+
+// splitInsert splits a single-row insert statement into the part before VALUES,
+// the parenthesized row of values and the part after it
+func splitInsert(sqlstr string) (head, row, tail string, ok bool) {
+	s := strings.TrimSpace(sqlstr)
+	if len(s) < 6 || !strings.EqualFold(s[:6], "insert") {
+		return
+	}
+	lower := strings.ToLower(s)
+	for i := strings.Index(lower, "values"); i >= 0; {
+		before := i == 0 || !isIdentRune(rune(lower[i-1]))
+		after := strings.TrimLeftFunc(s[i+6:], unicode.IsSpace)
+		if before && strings.HasPrefix(after, "(") {
+			start := len(s) - len(after)
+			depth, quote := 0, byte(0)
+			for j := start; j < len(s); j++ {
+				c := s[j]
+				switch {
+				case quote != 0:
+					if c == quote {
+						quote = 0
+					}
+				case c == '\'' || c == '"' || c == '`':
+					quote = c
+				case c == '(':
+					depth++
+				case c == ')':
+					depth--
+					if depth == 0 {
+						return s[:i], s[start : j+1], s[j+1:], true
+					}
+				}
+			}
+			return
+		}
+		next := strings.Index(lower[i+6:], "values")
+		if next < 0 {
+			break
+		}
+		i = i + 6 + next
+	}
+	return
+}
+
+// insertTarget returns the table and columns following "insert into" in head
+func insertTarget(head string) (string, bool) {
+	s := strings.TrimSpace(strings.TrimSpace(head)[6:])
+	if len(s) < 5 || !strings.EqualFold(s[:4], "into") || isIdentRune(rune(s[4])) {
+		return "", false
+	}
+	return strings.TrimSpace(s[4:]), true
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// executeBatchMultiRow executes the batch of a single-row insert as multi-row inserts,
+// falling back to ExecuteBatchContext when the statement or the database does not allow it.
+// Several chunks are executed in a transaction, unless g is one, so that a failing chunk
+// does not leave the rows of the previous chunks inserted
+func executeBatchMultiRow(ctx context.Context, g DBhandle, sqlstr string, args [][]any) (r []sql.Result, err error) {
+	d := GetDialect(g.GetDBType())
+	maxRows, ok := d.BatchLimit()
+	head, row, tail, split := splitInsert(sqlstr)
+	if !ok || !split || len(args) < 2 || d.MultiRowInsert(head, row, tail, 1) == "" {
+		return g.ExecuteBatchContext(ctx, sqlstr, args)
+	}
+	_, width := util.RewritePlaceholders(row, nil)
+	if _, n := util.RewritePlaceholders(tail, nil); width == 0 || n > 0 {
+		return g.ExecuteBatchContext(ctx, sqlstr, args)
+	}
+	size := len(args)
+	if params := d.ParamLimit(); params > 0 {
+		size = params / width
+	}
+	if maxRows > 0 && size > maxRows {
+		size = maxRows
+	}
+	if size < 2 {
+		return g.ExecuteBatchContext(ctx, sqlstr, args)
+	}
+	if _, istx := g.(Transaction); !istx && len(args) > size {
+		var x Transaction
+		if x, err = NewTransactionContext(ctx, g, nil); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				x.Rollback()
+				r = nil
+			} else {
+				err = x.Commit()
+			}
+		}()
+		g = x
+	}
+	r = make([]sql.Result, 0, (len(args)+size-1)/size)
+	for i := 0; i < len(args); i += size {
+		chunk := args[i:min(i+size, len(args))]
+		chunkArgs := make([]any, 0, len(chunk)*width)
+		for _, record := range chunk {
+			chunkArgs = append(chunkArgs, record...)
+		}
+		s := d.MultiRowInsert(head, row, tail, len(chunk))
+		if Logger.IsVaild {
+			Logger.Debug("[BATCH CHUNK]["+s+"]", len(chunk))
+		}
+		rs, err := g.ExecuteUpdateContext(ctx, s, chunkArgs...)
+		if err != nil {
+			return r, err
+		}
+		r = append(r, rs)
+	}
+	return r, nil
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	. "github.com/donnie4w/gdao/base"
+	"strings"
+	"testing"
+)
+
+func Test_splitInsert(t *testing.T) {
+	tests := []struct {
+		sql             string
+		head, row, tail string
+		ok              bool
+	}{
+		{"insert into hstest(id,name)values(?,?)", "insert into hstest(id,name)", "(?,?)", "", true},
+		{" insert  into hstest(id,name )values(?,?)", "insert  into hstest(id,name )", "(?,?)", "", true},
+		{"INSERT INTO hstest(myvalues) VALUES (?, lower(?)) on conflict (id) do nothing", "INSERT INTO hstest(myvalues) ", "(?, lower(?))", " on conflict (id) do nothing", true},
+		{"insert into hstest(name)values(')(')", "insert into hstest(name)", "(')(')", "", true},
+		{"insert into hstest select * from hstest2", "", "", "", false},
+		{"update hstest set name=? where id in (values(1))", "", "", "", false},
+	}
+	for _, tt := range tests {
+		head, row, tail, ok := splitInsert(tt.sql)
+		if head != tt.head || row != tt.row || tail != tt.tail || ok != tt.ok {
+			t.Fatalf("%s: %q %q %q %v", tt.sql, head, row, tail, ok)
+		}
+	}
+}
+
+func Test_MultiRowInsert(t *testing.T) {
+	tests := []struct {
+		dbtype DBType
+		tail   string
+		sql    string
+	}{
+		{MYSQL, "", "insert into hstest(id,name)values(?,?),(?,?),(?,?)"},
+		{POSTGRESQL, " on conflict do nothing", "insert into hstest(id,name)values(?,?),(?,?),(?,?) on conflict do nothing"},
+		{ORACLE, "", "insert all into hstest(id,name) values(?,?) into hstest(id,name) values(?,?) into hstest(id,name) values(?,?) select 1 from dual"},
+		{ORACLE, " log errors", ""},
+	}
+	for _, tt := range tests {
+		if s := GetDialect(tt.dbtype).MultiRowInsert("insert into hstest(id,name)", "(?,?)", tt.tail, 3); s != tt.sql {
+			t.Fatalf("%v:\n got: %s\nwant: %s", tt.dbtype, s, tt.sql)
+		}
+	}
+}
+
+func Test_executeBatchMultiRow(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbtype DBType
+		width  int
+		rows   int
+		chunks []int
+		prefix string
+	}{
+		{"single row", SQLITE, 3, 1, []int{3}, "insert into hstest(id,name,age)values(?,?,?)"},
+		{"parameter limit", SQLITE, 3, 333, []int{999}, "insert into hstest(id,name,age)values(?,?,?),(?,?,?)"},
+		{"partial chunk", SQLITE, 3, 334, []int{999, 3}, "insert into hstest(id,name,age)values(?,?,?),"},
+		{"row limit", SQLSERVER, 1, 1001, []int{1000, 1}, "insert into hstest(id)values(@p1),(@p2)"},
+		{"insert all", ORACLE, 2, 1001, []int{2000, 2}, "insert all into hstest(id,name) values(:v1,:v2) into hstest(id,name) values(:v3,:v4)"},
+		{"no parameter limit", DERBY, 3, 1000, []int{3000}, "insert into hstest(id,name,age)values(?,?,?),(?,?,?)"},
+		{"no multi-row insert", SYBASE, 2, 3, []int{2, 2, 2}, "insert into hstest(id,name)values(?,?)"},
+	}
+	columns := []string{"id", "name", "age"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := useTestDB(t, tt.dbtype, nil)
+			sqlstr := "insert into hstest(" + strings.Join(columns[:tt.width], ",") + ")values(" + marks(tt.width) + ")"
+			args := make([][]any, tt.rows)
+			for i := range args {
+				args[i] = []any{int64(i), "a", int64(18)}[:tt.width]
+			}
+			rs, err := executeBatchMultiRow(context.Background(), GetDefaultDBHandle(), sqlstr, args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := d.statements()
+			if len(tt.chunks) > 1 && tt.dbtype != SYBASE {
+				if len(s) < 2 || s[0].sql != "begin" || s[len(s)-1].sql != "commit" {
+					t.Fatal(s)
+				}
+				s = s[1 : len(s)-1]
+			}
+			if len(s) != len(tt.chunks) || len(rs) != len(tt.chunks) {
+				t.Fatal(len(rs), s)
+			}
+			for i, st := range s {
+				if len(st.args) != tt.chunks[i] || (i == 0 && !strings.HasPrefix(st.sql, tt.prefix)) {
+					t.Fatalf("chunk %d: %d arguments, want %d: %.100s", i, len(st.args), tt.chunks[i], st.sql)
+				}
+			}
+			if last := s[len(s)-1].args; last[len(last)-tt.width] != int64(tt.rows-1) {
+				t.Fatal(last)
+			}
+		})
+	}
+}
+
+func Test_executeBatchMultiRowTx(t *testing.T) {
+	d := useTestDB(t, SQLITE, nil)
+	sqlstr := "insert into hstest(id,name,age)values(?,?,?)"
+	args := make([][]any, 334)
+	for i := range args {
+		args[i] = []any{int64(i), "a", int64(18)}
+	}
+	failed := errors.New("failed")
+	d.execErr = func(sql string, args []driver.Value) error {
+		if len(args) == 3 {
+			return failed
+		}
+		return nil
+	}
+	rs, err := executeBatchMultiRow(context.Background(), GetDefaultDBHandle(), sqlstr, args)
+	if err != failed || rs != nil {
+		t.Fatal(rs, err)
+	}
+	checkChunks(t, d, "rollback")
+
+	d.execErr = nil
+	tx, err := NewTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs, err = executeBatchMultiRow(context.Background(), tx, sqlstr, args); err != nil || len(rs) != 2 {
+		t.Fatal(rs, err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	checkChunks(t, d, "commit")
+}
+
+// checkChunks fails the test unless the two chunks of the batch were executed in a transaction ended by end
+func checkChunks(t *testing.T, d *testDriver, end string) {
+	t.Helper()
+	s := d.statements()
+	if len(s) != 4 || s[0].sql != "begin" || len(s[1].args) != 999 || len(s[2].args) != 3 || s[3].sql != end {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql"
+	"fmt"
+	. "github.com/donnie4w/gdao/base"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// BulkUpdate updates many rows, each with its own values, with one statement per chunk of rows:
+//
+//	update hstest set name=case id when ? then ? when ? then ? else name end where id in (?,?)
+//
+// PostgreSQL, Greenplum and openGauss update the rows from a list of values when every row sets the same columns:
+//
+//	update hstest set name=v.gdao_1 from (values ((null::hstest).id,(null::hstest).name),($1,$2),($3,$4)) v(gdao_0,gdao_1) where hstest.id=v.gdao_0
+//
+// The rows are the entities given with the columns set on them, or else the rows added by AddBatch.
+// Every row must set keyColumn, which is not updated. The chunks respect the ParamLimit of the Dialect,
+// and one sql.Result is returned per chunk. The conditions of the Table restrict the updated rows,
+// the version column is incremented without being checked, and the audit columns of an update are set.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	for id, status := range statuses {
+//	    hs.SetId(id)
+//	    hs.SetStatus(status)
+//	    hs.AddBatch()
+//	}
+//	hs.BulkUpdate(hs.Id)
+func (t *Table[T]) BulkUpdate(keyColumn Column[T], rows ...*T) ([]sql.Result, error) {
+	key := unqualify(keyColumn.Name())
+	values, err := t.bulkRows(key, rows)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+	columns := make([]string, 0)
+	for i, row := range values {
+		if _, ok := row[key]; !ok {
+			return nil, fmt.Errorf("the key column %s of the table %s is not set in the row %d", key, t.tableName, i)
+		}
+		for column := range row {
+			if column != key && column != t.version && !slices.Contains(columns, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no column of the table %s is set besides its key", t.tableName)
+	}
+	slices.Sort(columns)
+	dense := true
+	for _, row := range values {
+		for _, column := range columns {
+			if _, ok := row[column]; !ok {
+				dense = false
+			}
+		}
+	}
+
+	g := t.getDB(false)
+	if g == nil {
+		return nil, errInit
+	}
+	d := GetDialect(g.GetDBType())
+	pg := false
+	switch g.GetDBType() {
+	case POSTGRESQL, GREENPLUM, OPENGAUSS:
+		pg = dense
+	}
+	where, whereArgs := t.whereClause()
+	maxRows, _ := d.BatchLimit()
+	perRow := 1 + 2*len(columns)
+	if pg {
+		perRow = 1 + len(columns)
+	}
+	size := len(values)
+	if params := d.ParamLimit(); params > 0 {
+		size = max((params-len(whereArgs))/perRow, 1)
+	}
+	if maxRows > 0 && size > maxRows {
+		size = maxRows
+	}
+
+	t.clearExpire()
+	r := make([]sql.Result, 0, (len(values)+size-1)/size)
+	for i := 0; i < len(values); i += size {
+		chunk := values[i:min(i+size, len(values))]
+		var sqlstr string
+		var args []any
+		if pg {
+			sqlstr, args = t.bulkValuesSql(key, columns, chunk, where, whereArgs)
+		} else {
+			sqlstr, args = t.bulkCaseSql(key, columns, chunk, where, whereArgs)
+		}
+		if Logger.IsVaild {
+			Logger.Debug("[BULK UPDATE]["+sqlstr+"]", args)
+		}
+		rs, err := g.ExecuteUpdateContext(t.getContext(), sqlstr, args...)
+		if err != nil {
+			return r, err
+		}
+		r = append(r, rs)
+	}
+	return r, nil
+}
+
+// bulkRows returns the values of the rows of BulkUpdate, set on the entities with the key loaded
+// from their rows or else added by AddBatch, with the audit columns of an update
+func (t *Table[T]) bulkRows(key string, rows []*T) ([]map[string]any, error) {
+	if len(rows) > 0 {
+		r := make([]map[string]any, len(rows))
+		for i, row := range rows {
+			e, ok := any(row).(interface{ table() *Table[T] })
+			if !ok || row == nil {
+				return nil, fmt.Errorf("the row %d of the bulk update of the table %s is not a standardized entity class", i, t.tableName)
+			}
+			r[i] = e.table().auditValues(false)
+			if v, ok := e.table().loaded[key]; ok {
+				if _, set := r[i][key]; !set {
+					r[i] = maps.Clone(r[i])
+					r[i][key] = v
+				}
+			}
+		}
+		return r, nil
+	}
+	var created []string
+	if audit := t.getAudit(); audit != nil {
+		created = []string{audit.CreatedAt, audit.CreatedBy}
+	}
+	r := make([]map[string]any, len(t.batchrows))
+	for i, record := range t.batchrows {
+		r[i] = make(map[string]any, len(record))
+		for column, v := range record {
+			if !slices.Contains(created, column) {
+				r[i][column] = v
+			}
+		}
+	}
+	return r, nil
+}
+
+// table returns the Table embedded by the entity classes
+func (t *Table[T]) table() *Table[T] {
+	return t
+}
+
+// bulkSet returns the assignment of the version column of a bulk update, if any
+func (t *Table[T]) bulkSet() string {
+	if t.version == "" {
+		return ""
+	}
+	return "," + t.version + "=" + t.version + "+1"
+}
+
+// bulkCaseSql returns the update of the rows setting every column with a case on the key, and its arguments
+func (t *Table[T]) bulkCaseSql(key string, columns []string, rows []map[string]any, where string, whereArgs []any) (string, []any) {
+	args := make([]any, 0, len(rows)*(2*len(columns)+1)+len(whereArgs))
+	sets := make([]string, 0, len(columns))
+	for _, column := range columns {
+		var b strings.Builder
+		b.WriteString(column + "=case " + key)
+		for _, row := range rows {
+			if v, ok := row[column]; ok {
+				b.WriteString(" when ? then ?")
+				args = append(args, row[key], v)
+			}
+		}
+		b.WriteString(" else " + column + " end")
+		sets = append(sets, b.String())
+	}
+	args = append(args, whereArgs...)
+	for _, row := range rows {
+		args = append(args, row[key])
+	}
+	condition := " where " + key + " in (" + marks(len(rows)) + ")"
+	if where != "" {
+		condition = " where (" + strings.TrimPrefix(where, " where ") + ") and " + key + " in (" + marks(len(rows)) + ")"
+	}
+	return "update " + t.tableName + " set " + strings.Join(sets, ",") + t.bulkSet() + condition, args
+}
+
+// bulkValuesSql returns the update of the rows from a list of values, and its arguments.
+// The first row of the list holds typed nulls matching no row, so that the values get the types of the columns
+func (t *Table[T]) bulkValuesSql(key string, columns []string, rows []map[string]any, where string, whereArgs []any) (string, []any) {
+	names := make([]string, len(columns)+1)
+	typed := make([]string, len(columns)+1)
+	sets := make([]string, len(columns))
+	for i, column := range append([]string{key}, columns...) {
+		names[i] = "gdao_" + strconv.Itoa(i)
+		typed[i] = "(null::" + t.tableName + ")." + column
+		if i > 0 {
+			sets[i-1] = column + "=v." + names[i]
+		}
+	}
+	args := make([]any, 0, len(rows)*len(names)+len(whereArgs))
+	row := "(" + marks(len(names)) + ")"
+	list := make([]string, 0, len(rows)+1)
+	list = append(list, "("+strings.Join(typed, ",")+")")
+	for _, r := range rows {
+		args = append(args, r[key])
+		for _, column := range columns {
+			args = append(args, r[column])
+		}
+		list = append(list, row)
+	}
+	condition := " where " + t.tableName + "." + key + "=v.gdao_0"
+	if where != "" {
+		condition = condition + " and (" + strings.TrimPrefix(where, " where ") + ")"
+	}
+	return "update " + t.tableName + " set " + strings.Join(sets, ",") + t.bulkSet() + " from (values " + strings.Join(list, ",") +
+		") v(" + strings.Join(names, ",") + ")" + condition, append(args, whereArgs...)
+}
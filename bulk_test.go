@@ -0,0 +1,164 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	. "github.com/donnie4w/gdao/base"
+	"strings"
+	"testing"
+)
+
+func Test_bulkRows(t *testing.T) {
+	d := useTestDB(t, SQLITE, nil)
+	hs := newHstest()
+	hs.SetId(1).SetName("a")
+	hs.AddBatch()
+	hs.SetId(2).SetAge(20)
+	hs.AddBatch()
+	if _, err := hs.BulkUpdate(hs.ID); err != nil {
+		t.Fatal(err)
+	}
+	s := d.statements()
+	if len(s) != 1 {
+		t.Fatal(s)
+	}
+	checkSql(t, s[0].sql, toAny(s[0].args),
+		"update hstest set age=case id when ? then ? else age end,name=case id when ? then ? when ? then ? else name end where id in (?,?)",
+		int64(2), int64(20), int64(1), "a", int64(2), "a", int64(1), int64(2))
+
+	if _, err := hs.ExecBatch(); err != nil {
+		t.Fatal(err)
+	}
+	s = d.statements()
+	if len(s) != 2 || s[0].String() != " insert  into hstest(id,name,age )values(?,?,?)[1 a <nil>]" ||
+		s[1].String() != " insert  into hstest(id,name,age )values(?,?,?)[2 a 20]" {
+		t.Fatal(s)
+	}
+
+	_, err := hs.BulkUpdate(hs.ID, newHstest().SetId(1).SetName("a"), nil)
+	if err == nil || !strings.Contains(err.Error(), "row 1 ") {
+		t.Fatal(err)
+	}
+}
+
+func toAny[V any](vs []V) []any {
+	r := make([]any, len(vs))
+	for i, v := range vs {
+		r[i] = v
+	}
+	return r
+}
+
+func Test_bulkCaseSql(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		rows    []map[string]any
+		where   bool
+		sql     string
+		args    []any
+	}{
+		{"dense", []string{"age", "name"}, []map[string]any{{"id": 1, "age": 10, "name": "a"}, {"id": 2, "age": 20, "name": "b"}}, false,
+			"update hstest set age=case id when ? then ? when ? then ? else age end,name=case id when ? then ? when ? then ? else name end,version=version+1 where id in (?,?)",
+			[]any{1, 10, 2, 20, 1, "a", 2, "b", 1, 2}},
+		{"missing column", []string{"age", "name"}, []map[string]any{{"id": 1, "age": 10}, {"id": 2, "name": "b"}}, false,
+			"update hstest set age=case id when ? then ? else age end,name=case id when ? then ? else name end,version=version+1 where id in (?,?)",
+			[]any{1, 10, 2, "b", 1, 2}},
+		{"where", []string{"name"}, []map[string]any{{"id": 1, "name": "a"}}, true,
+			"update hstest set name=case id when ? then ? else name end,version=version+1 where (age>? or age<?) and id in (?)",
+			[]any{1, "a", 60, 10, 1}},
+	}
+	for _, tt := range tests {
+		hs := newHstest()
+		hs.UseVersion(hs.VERSION)
+		if tt.where {
+			hs.Where(Or(hs.AGE.GT(60), hs.AGE.LT(10)))
+		}
+		where, whereArgs := hs.whereClause()
+		sqlstr, args := hs.bulkCaseSql("id", tt.columns, tt.rows, where, whereArgs)
+		t.Run(tt.name, func(t *testing.T) { checkSql(t, sqlstr, args, tt.sql, tt.args...) })
+	}
+}
+
+func Test_bulkValuesSql(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		rows    []map[string]any
+		where   bool
+		sql     string
+		args    []any
+	}{
+		{"one column", []string{"name"}, []map[string]any{{"id": 1, "name": "a"}, {"id": 2, "name": "b"}}, false,
+			"update hstest set name=v.gdao_1 from (values ((null::hstest).id,(null::hstest).name),(?,?),(?,?)) v(gdao_0,gdao_1) where hstest.id=v.gdao_0",
+			[]any{1, "a", 2, "b"}},
+		{"where", []string{"age", "name"}, []map[string]any{{"id": 1, "age": 10, "name": "a"}}, true,
+			"update hstest set age=v.gdao_1,name=v.gdao_2 from (values ((null::hstest).id,(null::hstest).age,(null::hstest).name),(?,?,?)) v(gdao_0,gdao_1,gdao_2) where hstest.id=v.gdao_0 and (age>? or age<?)",
+			[]any{1, 10, "a", 60, 10}},
+	}
+	for _, tt := range tests {
+		hs := newHstest()
+		if tt.where {
+			hs.Where(Or(hs.AGE.GT(60), hs.AGE.LT(10)))
+		}
+		where, whereArgs := hs.whereClause()
+		sqlstr, args := hs.bulkValuesSql("id", tt.columns, tt.rows, where, whereArgs)
+		t.Run(tt.name, func(t *testing.T) { checkSql(t, sqlstr, args, tt.sql, tt.args...) })
+	}
+}
+
+func Test_BulkUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		dbtype DBType
+		rows   int
+		dense  bool
+		where  bool
+		chunks []int
+		prefix string
+	}{
+		{"single row", SQLITE, 1, true, false, []int{3}, "update hstest set name=case id when ? then ?"},
+		{"parameter limit", SQLITE, 333, true, false, []int{999}, "update hstest set name=case"},
+		{"partial chunk", SQLITE, 334, true, false, []int{999, 3}, "update hstest set name=case"},
+		{"where argument", SQLITE, 333, true, true, []int{997, 4}, "update hstest set name=case"},
+		{"row limit", ORACLE, 1001, true, false, []int{3000, 3}, "update hstest set name=case"},
+		{"values", POSTGRESQL, 2, true, false, []int{4}, "update hstest set name=v.gdao_1 from (values"},
+		{"cockroachdb", COCKROACHDB, 2, true, false, []int{6}, "update hstest set name=case id when ? then ?"},
+		{"sqlserver", SQLSERVER, 700, true, false, []int{2100}, "update hstest set name=case id when @p1 then @p2"},
+		{"not dense", POSTGRESQL, 2, false, false, []int{8}, "update hstest set age=case id when $1 then $2 else age end,name=case"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := useTestDB(t, tt.dbtype, nil)
+			hs := newHstest()
+			if tt.where {
+				hs.Where(hs.AGE.GT(18))
+			}
+			rows := make([]*hstest, tt.rows)
+			for i := range rows {
+				rows[i] = newHstest().SetId(int64(i)).SetName("a")
+			}
+			if !tt.dense {
+				rows[0].SetAge(10)
+			}
+			rs, err := hs.BulkUpdate(hs.ID, rows...)
+			if err != nil || len(rs) != len(tt.chunks) {
+				t.Fatal(len(rs), err)
+			}
+			s := d.statements()
+			if len(s) != len(tt.chunks) {
+				t.Fatal(s)
+			}
+			for i, st := range s {
+				if len(st.args) != tt.chunks[i] || !strings.HasPrefix(st.sql, tt.prefix) {
+					t.Fatalf("chunk %d: %d arguments, want %d: %.100s", i, len(st.args), tt.chunks[i], st.sql)
+				}
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/donnie4w/gdao/gdaoSlave"
 	"github.com/donnie4w/gdao/gdaoStruct"
 	"github.com/donnie4w/gdao/util"
+	"github.com/donnie4w/gofer/hashmap"
 )
 
 func NewDBHandle(db *sql.DB, dbtype DBType) DBhandle {
@@ -48,6 +49,27 @@ func GetDefaultDBHandle() DBhandle {
 //	gdao.Init(db, gdao.MYSQL)
 func Init(db *sql.DB, dbtype DBType) {
 	defaultDBhandle = newdbhandle(db, dbtype)
+	if !dataSourceNames.Has(db) {
+		dataSourceNames.Put(db, "default")
+	}
+}
+
+var dataSourceNames = hashmap.NewMapL[*sql.DB, string]()
+
+// NameDataSource sets the name of the database, seen by the interceptors as Statement.DataSource.
+// The database of Init is named "default" unless it is named by NameDataSource.
+//
+// Example:
+//
+//	gdao.NameDataSource(orderDB, "orders")
+//	gdao.BindDataSource(orderDB, gdao.MYSQL, "orders", "order_items")
+func NameDataSource(db *sql.DB, name string) {
+	dataSourceNames.Put(db, name)
+}
+
+func dataSourceName(db *sql.DB) string {
+	name, _ := dataSourceNames.Get(db)
+	return name
 }
 
 var dbContainer = newContainer()
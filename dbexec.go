@@ -8,10 +8,12 @@
 package gdao
 
 import (
+	"context"
 	"database/sql"
 	. "github.com/donnie4w/gdao/base"
 	"github.com/donnie4w/gdao/util"
 	"github.com/donnie4w/gofer/pool/buffer"
+	"iter"
 )
 
 var bufpool = buffer.NewPool[[]any](func() *[]any {
@@ -29,16 +31,16 @@ func newAnys(length int) (r *[]any) {
 	}
 }
 
-func executeQueryBeans(tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (databases []*DataBean, err error) {
+func executeQueryBeans(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (databases []*DataBean, err error) {
 	if tx == nil && db == nil {
 		return nil, errInit
 	}
 	//defer util.Recover(&err)
 	var rows *sql.Rows
 	if tx != nil {
-		rows, err = tx.Query(sqlstr, args...)
+		rows, err = tx.QueryContext(ctx, sqlstr, args...)
 	} else {
-		rows, err = db.Query(sqlstr, args...)
+		rows, err = db.QueryContext(ctx, sqlstr, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -67,16 +69,64 @@ func executeQueryBeans(tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (data
 	return
 }
 
-func executeQueryBean(tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (dataBean *DataBean, err error) {
+func executeQueryRows(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (*sql.Rows, error) {
+	if tx == nil && db == nil {
+		return nil, errInit
+	}
+	if tx != nil {
+		return tx.QueryContext(ctx, sqlstr, args...)
+	}
+	return db.QueryContext(ctx, sqlstr, args...)
+}
+
+// executeQueryIter streams the rows of the query, the rows are closed when the
+// iteration is finished, fails or is stopped by the caller
+func executeQueryIter(rowsFunc func() (*sql.Rows, error)) iter.Seq2[*DataBean, error] {
+	return func(yield func(*DataBean, error) bool) {
+		rows, err := rowsFunc()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer rows.Close()
+		names, err := rows.Columns()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for rows.Next() {
+			databean := NewDataBean(len(names))
+			buff := newAnys(len(names))
+			for _, name := range names {
+				fb := NewFieldBeen()
+				*buff = append(*buff, &fb.FieldValue)
+				databean.Put(name, fb)
+			}
+			if err = rows.Scan(*buff...); err != nil {
+				yield(nil, err)
+				return
+			}
+			bufpool.Put(&buff)
+			if !yield(databean, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func executeQueryBean(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (dataBean *DataBean, err error) {
 	if tx == nil && db == nil {
 		return nil, errInit
 	}
 	//defer util.Recover(&err)
 	var rows *sql.Rows
 	if tx != nil {
-		rows, err = tx.Query(sqlstr, args...)
+		rows, err = tx.QueryContext(ctx, sqlstr, args...)
 	} else {
-		rows, err = db.Query(sqlstr, args...)
+		rows, err = db.QueryContext(ctx, sqlstr, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -103,34 +153,34 @@ func executeQueryBean(tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (dataB
 	return
 }
 
-func executeUpdate(tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (rs sql.Result, err error) {
+func executeUpdate(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (rs sql.Result, err error) {
 	if tx == nil && db == nil {
 		return nil, errInit
 	}
 	defer util.Recover(&err)
 	var stmtIns *sql.Stmt
 	if tx != nil {
-		stmtIns, err = tx.Prepare(sqlstr)
+		stmtIns, err = tx.PrepareContext(ctx, sqlstr)
 	} else {
-		stmtIns, err = db.Prepare(sqlstr)
+		stmtIns, err = db.PrepareContext(ctx, sqlstr)
 	}
 	if err != nil {
 		return
 	}
 	defer stmtIns.Close()
-	return stmtIns.Exec(args...)
+	return stmtIns.ExecContext(ctx, args...)
 }
 
-func executeBatch(tx *sql.Tx, db *sql.DB, sqlstr string, args [][]any) (r []sql.Result, err error) {
+func executeBatch(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args [][]any) (r []sql.Result, err error) {
 	if tx == nil && db == nil {
 		return nil, errInit
 	}
 	defer util.Recover(&err)
 	var stmtIns *sql.Stmt
 	if tx != nil {
-		stmtIns, err = tx.Prepare(sqlstr)
+		stmtIns, err = tx.PrepareContext(ctx, sqlstr)
 	} else {
-		stmtIns, err = db.Prepare(sqlstr)
+		stmtIns, err = db.PrepareContext(ctx, sqlstr)
 	}
 	if err != nil {
 		return nil, err
@@ -138,7 +188,7 @@ func executeBatch(tx *sql.Tx, db *sql.DB, sqlstr string, args [][]any) (r []sql.
 	defer stmtIns.Close()
 	r = make([]sql.Result, 0)
 	for _, record := range args {
-		if rs, er := stmtIns.Exec(record...); er == nil {
+		if rs, er := stmtIns.ExecContext(ctx, record...); er == nil {
 			r = append(r, rs)
 		} else {
 			err = er
@@ -0,0 +1,102 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func testRowsOf(n int) [][]driver.Value {
+	rows := make([][]driver.Value, n)
+	for i := range rows {
+		rows[i] = []driver.Value{int64(i + 1), "a"}
+	}
+	return rows
+}
+
+func Test_SelectsIter(t *testing.T) {
+	d := useTestDB(t, MYSQL, []string{"id", "name"}, testRowsOf(5)...)
+	hs := newHstest()
+	var ids []int64
+	for h, err := range hs.SelectsIter(hs.ID, hs.NAME) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, h.GetId())
+		if len(ids) == 2 {
+			break
+		}
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 || d.closedRows() != 1 {
+		t.Fatal(ids, d.closedRows())
+	}
+
+	n := 0
+	err := hs.SelectsEach(func(h *hstest) bool {
+		n++
+		return h.GetId() < 3
+	})
+	if err != nil || n != 3 || d.closedRows() != 2 {
+		t.Fatal(err, n, d.closedRows())
+	}
+
+	n = 0
+	for _, err = range hs.SelectsIter() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 5 || d.closedRows() != 3 {
+		t.Fatal(n, d.closedRows())
+	}
+
+	d.queryErr = errors.New("query failed")
+	if err = hs.SelectsEach(func(*hstest) bool { return true }); err == nil || err.Error() != "query failed" {
+		t.Fatal(err)
+	}
+}
+
+func Test_SelectsIterTransaction(t *testing.T) {
+	d := useTestDB(t, MYSQL, []string{"id", "name"}, testRowsOf(3)...)
+	tx, err := NewTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs := newHstest()
+	hs.UseTransaction(tx)
+	hs.Where(hs.ID.GT(0))
+	for h, err := range hs.SelectsIter() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		hs.Reset()
+		if _, err = hs.SetName("b").Where(hs.ID.EQ(h.GetId())).Update(); err != nil {
+			t.Fatal(err)
+		}
+		break
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	s := d.statements()
+	want := []string{"begin", " select id,name,age,version from hstest where id>?[0]", "update hstest set name=? where id=?[b 1]", "commit"}
+	if len(s) != len(want) {
+		t.Fatal(s)
+	}
+	for i, st := range s {
+		if st.String() != want[i] {
+			t.Fatalf("\n got: %s\nwant: %s", st, want[i])
+		}
+	}
+	if d.closedRows() != 1 {
+		t.Fatal(d.closedRows())
+	}
+}
@@ -8,8 +8,10 @@
 package gdao
 
 import (
+	"context"
 	"database/sql"
 	"github.com/donnie4w/gdao/base"
+	"iter"
 )
 
 // ExecuteQuery executes an SQL query and returns the parsed results.
@@ -19,7 +21,15 @@ import (
 // The function returns a pointer to a value of type *T, which is typically a pointer to a struct that holds the query results.
 // If there's an error, it returns nil and the specific error information; otherwise, it returns a filled result object and nil.
 func ExecuteQuery[T any](sql string, args ...any) (r *T, err error) {
-	if databean := defaultDBhandle.ExecuteQueryBean(sql, args...); databean.GetError() == nil && databean.Len() > 0 {
+	return ExecuteQueryContext[T](context.Background(), sql, args...)
+}
+
+// ExecuteQueryContext is like ExecuteQuery but uses ctx to cancel the query or enforce its deadline.
+func ExecuteQueryContext[T any](ctx context.Context, sql string, args ...any) (r *T, err error) {
+	if defaultDBhandle == nil {
+		return nil, errInit
+	}
+	if databean := defaultDBhandle.ExecuteQueryBeanContext(ctx, sql, args...); databean.GetError() == nil && databean.Len() > 0 {
 		r = new(T)
 		err = databean.ScanAndFree(r)
 	} else {
@@ -35,7 +45,15 @@ func ExecuteQuery[T any](sql string, args ...any) (r *T, err error) {
 // The function returns a slice of pointers to values of type T, where each element represents one row of the query results.
 // If there's an error, it returns nil and the specific error information; otherwise, it returns a slice of filled result objects and nil.
 func ExecuteQueryList[T any](sql string, args ...any) (r []*T, err error) {
-	if databeans := defaultDBhandle.ExecuteQueryBeans(sql, args...); databeans.GetError() == nil && databeans.Len() > 0 {
+	return ExecuteQueryListContext[T](context.Background(), sql, args...)
+}
+
+// ExecuteQueryListContext is like ExecuteQueryList but uses ctx to cancel the query or enforce its deadline.
+func ExecuteQueryListContext[T any](ctx context.Context, sql string, args ...any) (r []*T, err error) {
+	if defaultDBhandle == nil {
+		return nil, errInit
+	}
+	if databeans := defaultDBhandle.ExecuteQueryBeansContext(ctx, sql, args...); databeans.GetError() == nil && databeans.Len() > 0 {
 		r = make([]*T, 0)
 		for _, databean := range databeans.Beans {
 			t := new(T)
@@ -51,18 +69,96 @@ func ExecuteQueryList[T any](sql string, args ...any) (r []*T, err error) {
 	return
 }
 
+// ExecuteQueryIter executes an SQL query and returns an iterator over the parsed results.
+// Unlike ExecuteQueryList, the rows are not materialized up front: the underlying *sql.Rows is kept open
+// and each row is scanned into a new *T when the iterator advances. The rows are closed when the loop
+// finishes or breaks early. An error ends the iteration and is yielded with a nil value.
+//
+// Example:
+//
+//	for user, err := range gdao.ExecuteQueryIter[User]("select * from user where age>?", 18) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(user)
+//	}
+func ExecuteQueryIter[T any](sql string, args ...any) iter.Seq2[*T, error] {
+	return ExecuteQueryIterContext[T](context.Background(), sql, args...)
+}
+
+// ExecuteQueryIterContext is like ExecuteQueryIter but uses ctx to cancel the query or enforce its deadline.
+func ExecuteQueryIterContext[T any](ctx context.Context, sql string, args ...any) iter.Seq2[*T, error] {
+	if defaultDBhandle == nil {
+		return errSeq[T](errInit)
+	}
+	return scanSeq[T](defaultDBhandle.ExecuteQueryIter(ctx, sql, args...))
+}
+
+// ExecuteQueryEach executes an SQL query and calls f for each row of the parsed results as it is read.
+// The iteration stops when f returns false. It returns the first error encountered.
+func ExecuteQueryEach[T any](sql string, f func(*T) bool, args ...any) error {
+	return forEach(ExecuteQueryIter[T](sql, args...), f)
+}
+
+// ExecuteQueryEachContext is like ExecuteQueryEach but uses ctx to cancel the query or enforce its deadline.
+func ExecuteQueryEachContext[T any](ctx context.Context, sql string, f func(*T) bool, args ...any) error {
+	return forEach(ExecuteQueryIterContext[T](ctx, sql, args...), f)
+}
+
+func scanSeq[T any](seq iter.Seq2[*base.DataBean, error]) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for databean, err := range seq {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			t := new(T)
+			if err = databean.ScanAndFree(t); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+func errSeq[T any](err error) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		yield(nil, err)
+	}
+}
+
+func forEach[T any](seq iter.Seq2[*T, error], f func(*T) bool) error {
+	for t, err := range seq {
+		if err != nil {
+			return err
+		}
+		if !f(t) {
+			break
+		}
+	}
+	return nil
+}
+
 // ExecuteQueryBean executes an SQL query and returns a single DataBean object.
 // sql is the SQL query statement to execute.
 // args is an optional list of parameters to substitute placeholders in the SQL query.
 // The function returns a pointer to a DataBean object, which typically holds the data retrieved from a single row in the query results.
 // If there's an error, it returns nil and the specific error information; otherwise, it returns a filled DataBean object and nil.
 func ExecuteQueryBean(sql string, args ...any) *base.DataBean {
+	return ExecuteQueryBeanContext(context.Background(), sql, args...)
+}
+
+// ExecuteQueryBeanContext is like ExecuteQueryBean but uses ctx to cancel the query or enforce its deadline.
+func ExecuteQueryBeanContext(ctx context.Context, sql string, args ...any) *base.DataBean {
 	if defaultDBhandle == nil {
 		r := &base.DataBean{}
 		r.SetError(errInit)
 		return r
 	}
-	return defaultDBhandle.ExecuteQueryBean(sql, args...)
+	return defaultDBhandle.ExecuteQueryBeanContext(ctx, sql, args...)
 }
 
 // ExecuteQueryBeans executes an SQL query and returns a list of DataBean objects.
@@ -71,12 +167,17 @@ func ExecuteQueryBean(sql string, args ...any) *base.DataBean {
 // The function returns a slice of pointers to DataBean objects, where each element represents one row of the query results.
 // If there's an error, it returns nil and the specific error information; otherwise, it returns a slice of filled DataBean objects and nil.
 func ExecuteQueryBeans(sql string, args ...any) *base.DataBeans {
+	return ExecuteQueryBeansContext(context.Background(), sql, args...)
+}
+
+// ExecuteQueryBeansContext is like ExecuteQueryBeans but uses ctx to cancel the query or enforce its deadline.
+func ExecuteQueryBeansContext(ctx context.Context, sql string, args ...any) *base.DataBeans {
 	if defaultDBhandle == nil {
 		r := &base.DataBeans{}
 		r.SetError(errInit)
 		return r
 	}
-	return defaultDBhandle.ExecuteQueryBeans(sql, args...)
+	return defaultDBhandle.ExecuteQueryBeansContext(ctx, sql, args...)
 }
 
 // ExecuteUpdate executes an SQL update, insert, or delete statement.
@@ -85,10 +186,15 @@ func ExecuteQueryBeans(sql string, args ...any) *base.DataBeans {
 // The function returns the number of rows affected by the SQL statement and any error encountered.
 // If there's an error, it returns -1 and the specific error information; otherwise, it returns the number of affected rows and nil.
 func ExecuteUpdate(sql string, args ...any) (sql.Result, error) {
+	return ExecuteUpdateContext(context.Background(), sql, args...)
+}
+
+// ExecuteUpdateContext is like ExecuteUpdate but uses ctx to cancel the statement or enforce its deadline.
+func ExecuteUpdateContext(ctx context.Context, sql string, args ...any) (sql.Result, error) {
 	if defaultDBhandle == nil {
 		return nil, errInit
 	}
-	return defaultDBhandle.ExecuteUpdate(sql, args...)
+	return defaultDBhandle.ExecuteUpdateContext(ctx, sql, args...)
 }
 
 // ExecuteBatch executes a batch of SQL statements.
@@ -96,9 +202,18 @@ func ExecuteUpdate(sql string, args ...any) (sql.Result, error) {
 // args is a slice of slices, where each inner slice contains the arguments for a single SQL statement.
 // The function returns a slice of int64 values representing the number of rows affected by each SQL statement and any error encountered.
 // If there's an error, it returns nil and the specific error information; otherwise, it returns the slice of affected rows and nil.
+// With MultiRowBatch(true) an insert is executed as multi-row inserts and one result is returned per chunk of rows.
 func ExecuteBatch(sql string, args [][]any) ([]sql.Result, error) {
+	return ExecuteBatchContext(context.Background(), sql, args)
+}
+
+// ExecuteBatchContext is like ExecuteBatch but uses ctx to cancel the batch or enforce its deadline.
+func ExecuteBatchContext(ctx context.Context, sql string, args [][]any) ([]sql.Result, error) {
 	if defaultDBhandle == nil {
 		return nil, errInit
 	}
-	return defaultDBhandle.ExecuteBatch(sql, args)
+	if multiRowBatch {
+		return executeBatchMultiRow(ctx, defaultDBhandle, sql, args)
+	}
+	return defaultDBhandle.ExecuteBatchContext(ctx, sql, args)
 }
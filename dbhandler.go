@@ -8,9 +8,11 @@
 package gdao
 
 import (
+	"context"
 	"database/sql"
 	. "github.com/donnie4w/gdao/base"
 	"github.com/donnie4w/gdao/gdaoSlave"
+	"iter"
 )
 
 type dbHandler struct {
@@ -22,6 +24,7 @@ func init() {
 }
 
 func newdbhandle(db *sql.DB, dbtype DBType) DBhandle {
+	databases.Put(db, struct{}{})
 	return &dbHandler{gdbc: newGdbcHandle(nil, db, dbtype)}
 }
 
@@ -41,6 +44,10 @@ func (h *dbHandler) GetTransaction() (r Transaction, err error) {
 	return NewTransactionWithDBhandle(h)
 }
 
+func (h *dbHandler) GetTransactionContext(ctx context.Context, opts *sql.TxOptions) (r Transaction, err error) {
+	return NewTransactionContext(ctx, h, opts)
+}
+
 func (h *dbHandler) ExecuteQueryBean(sqlstr string, args ...any) *DataBean {
 	return h.gdbc.ExecuteQueryBean(sqlstr, args...)
 }
@@ -56,3 +63,23 @@ func (h *dbHandler) ExecuteUpdate(sqlstr string, args ...any) (sql.Result, error
 func (h *dbHandler) ExecuteBatch(sqlstr string, args [][]any) (r []sql.Result, err error) {
 	return h.gdbc.ExecuteBatch(sqlstr, args)
 }
+
+func (h *dbHandler) ExecuteQueryBeanContext(ctx context.Context, sqlstr string, args ...any) *DataBean {
+	return h.gdbc.ExecuteQueryBeanContext(ctx, sqlstr, args...)
+}
+
+func (h *dbHandler) ExecuteQueryBeansContext(ctx context.Context, sqlstr string, args ...any) (r *DataBeans) {
+	return h.gdbc.ExecuteQueryBeansContext(ctx, sqlstr, args...)
+}
+
+func (h *dbHandler) ExecuteUpdateContext(ctx context.Context, sqlstr string, args ...any) (sql.Result, error) {
+	return h.gdbc.ExecuteUpdateContext(ctx, sqlstr, args...)
+}
+
+func (h *dbHandler) ExecuteBatchContext(ctx context.Context, sqlstr string, args [][]any) (r []sql.Result, err error) {
+	return h.gdbc.ExecuteBatchContext(ctx, sqlstr, args)
+}
+
+func (h *dbHandler) ExecuteQueryIter(ctx context.Context, sqlstr string, args ...any) iter.Seq2[*DataBean, error] {
+	return h.gdbc.ExecuteQueryIter(ctx, sqlstr, args...)
+}
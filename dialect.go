@@ -0,0 +1,227 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"fmt"
+	. "github.com/donnie4w/gdao/base"
+	"strings"
+	"sync"
+)
+
+// Dialect describes how the statements built by gdao are written for one type of database.
+// Every DBType constant has a built-in Dialect; RegisterDialect adds or replaces one.
+// A custom Dialect usually embeds StandardDialect and overrides what its database does differently.
+type Dialect interface {
+	// Name returns the name of the database type, such as "mysql"
+	Name() string
+
+	// Placeholder returns the placeholder of the index-th parameter of a statement, starting with 1.
+	// The ? bind markers of the statements are rewritten when it is not "?"
+	Placeholder(index int) string
+
+	// Bind returns the value passed to the driver for the index-th parameter, starting with 1
+	Bind(index int, arg any) any
+
+	// Quote quotes an identifier such as a table or column name
+	Quote(identifier string) string
+
+	// Limit returns the clause limiting the number of selected rows
+	Limit(limit int64) (LimitClause, error)
+
+	// LimitOffset returns the clause selecting limit rows after skipping offset rows
+	LimitOffset(offset, limit int64) (LimitClause, error)
+
+	// Upsert returns the statement inserting one row of columns into table, or updating the row
+	// that already holds the same values of the conflict columns, see Table.Upsert
+	Upsert(table string, columns, conflicts []string) (string, error)
+
+	// Lock returns the clause locking the rows selected by a select statement, for update or share.
+	// noWait fails instead of waiting for the rows locked by other transactions, skipLocked skips them
+	Lock(share, noWait, skipLocked bool) (LockClause, error)
+
+	// Returning returns the clauses reading the columns of an inserted row back: output is
+	// inserted before VALUES and returning is appended to the statement. Both are empty when
+	// the generated key is read from sql.Result.LastInsertId. The ? markers of returning, as in
+	// " returning id into ?", are bound to sql.Out parameters receiving the values
+	Returning(columns []string) (output, returning string)
+
+	// ParamLimit returns the maximum number of parameters of one statement, such as a multi-row insert
+	// or a chunk of Table.BulkUpdate, 0 if the database has no such limit
+	ParamLimit() int
+
+	// BatchLimit returns the maximum number of rows of one multi-row insert, 0 if there is no row limit,
+	// and false if the database does not support multi-row inserts
+	BatchLimit() (rows int, ok bool)
+
+	// MultiRowInsert returns the statement inserting n rows, head, row and tail are the part before
+	// VALUES, the parenthesized row and the part after it of a single-row insert. An empty string
+	// means the statement cannot be rewritten
+	MultiRowInsert(head, row, tail string, n int) string
+}
+
+// LimitClause is the pagination of a select statement
+type LimitClause struct {
+	// Top is written right after select, such as "top 10 ", it must not contain placeholders
+	Top string
+
+	// Sql is appended to the statement, such as " LIMIT ? "
+	Sql string
+
+	// Args are the arguments of the placeholders in Sql
+	Args []any
+}
+
+// LockClause is the row locking of a select statement, see Table.ForUpdate
+type LockClause struct {
+	// Hint is written after the table name, such as " with (updlock, rowlock)"
+	Hint string
+
+	// Sql is appended to the statement after the pagination, such as " for update"
+	Sql string
+
+	// KeyColumn, such as "rowid", is set when the database cannot lock the rows of a paginated select:
+	// the rows are then selected by their KeyColumn in a paginated subquery, and locked by the outer statement
+	KeyColumn string
+}
+
+var dialects = struct {
+	sync.RWMutex
+	m map[DBType]Dialect
+}{m: make(map[DBType]Dialect)}
+
+// RegisterDialect registers the Dialect of a database type, replacing the built-in one if any.
+// A new database type only needs a DBType value that is not used by the constants of gdao.
+//
+// Example:
+//
+//	const CLICKHOUSE base.DBType = 100
+//
+//	type clickhouse struct{ gdao.StandardDialect }
+//
+//	func (c *clickhouse) Limit(limit int64) (gdao.LimitClause, error) {
+//	    return gdao.LimitClause{Sql: " LIMIT ? ", Args: []any{limit}}, nil
+//	}
+//
+//	gdao.RegisterDialect(CLICKHOUSE, &clickhouse{gdao.StandardDialect{DBName: "clickhouse"}})
+//	gdao.Init(db, CLICKHOUSE)
+func RegisterDialect(dbtype DBType, dialect Dialect) {
+	dialects.Lock()
+	defer dialects.Unlock()
+	dialects.m[dbtype] = dialect
+	clearSqlCache()
+}
+
+// GetDialect returns the Dialect of the database type, or a StandardDialect if none was registered
+func GetDialect(dbtype DBType) Dialect {
+	dialects.RLock()
+	defer dialects.RUnlock()
+	if d, ok := dialects.m[dbtype]; ok {
+		return d
+	}
+	return &StandardDialect{}
+}
+
+// DialectByName returns the registered Dialect with the name, case-insensitively,
+// or a StandardDialect if there is none
+func DialectByName(name string) Dialect {
+	dialects.RLock()
+	defer dialects.RUnlock()
+	for _, d := range dialects.m {
+		if strings.EqualFold(d.Name(), name) {
+			return d
+		}
+	}
+	return &StandardDialect{DBName: name}
+}
+
+// StandardDialect writes standard SQL: ? placeholders, double-quoted identifiers,
+// OFFSET ... FETCH pagination, MERGE upserts and FOR UPDATE locks.
+// It is meant to be embedded by custom dialects.
+type StandardDialect struct {
+	DBName string
+}
+
+func (d *StandardDialect) Name() string {
+	return d.DBName
+}
+
+func (d *StandardDialect) Placeholder(index int) string {
+	return "?"
+}
+
+func (d *StandardDialect) Bind(index int, arg any) any {
+	return arg
+}
+
+func (d *StandardDialect) Quote(identifier string) string {
+	return `"` + identifier + `"`
+}
+
+func (d *StandardDialect) Limit(limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " FETCH FIRST ? ROWS ONLY ", Args: []any{limit}}, nil
+}
+
+func (d *StandardDialect) LimitOffset(offset, limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " OFFSET ? ROWS FETCH NEXT ? ROWS ONLY ", Args: []any{offset, limit}}, nil
+}
+
+func (d *StandardDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	return mergeUpsert(table, columns, conflicts, "(values ("+marks(len(columns))+")) s("+strings.Join(columns, ",")+")", "")
+}
+
+func (d *StandardDialect) Lock(share, noWait, skipLocked bool) (LockClause, error) {
+	return LockClause{Sql: lockClause(" for update", " for share", share, noWait, skipLocked)}, nil
+}
+
+func (d *StandardDialect) Returning(columns []string) (output, returning string) {
+	return "", ""
+}
+
+// ParamLimit : 999, the lowest limit of the supported databases, that of SQLite before 3.32
+func (d *StandardDialect) ParamLimit() int {
+	return 999
+}
+
+func (d *StandardDialect) BatchLimit() (rows int, ok bool) {
+	return 0, false
+}
+
+func (d *StandardDialect) MultiRowInsert(head, row, tail string, n int) string {
+	builder := strings.Builder{}
+	builder.WriteString(head + "values")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			builder.WriteString(",")
+		}
+		builder.WriteString(row)
+	}
+	builder.WriteString(tail)
+	return builder.String()
+}
+
+func lockClause(update, share string, isShare, noWait, skipLocked bool) string {
+	s := update
+	if isShare {
+		s = share
+	}
+	if noWait {
+		s = s + " nowait"
+	} else if skipLocked {
+		s = s + " skip locked"
+	}
+	return s
+}
+
+func marks(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func errUnsupported(d Dialect, feature string) error {
+	return fmt.Errorf("%s is not supported by the %s dialect", feature, d.Name())
+}
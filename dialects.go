@@ -0,0 +1,420 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql"
+	. "github.com/donnie4w/gdao/base"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	for dbtype, d := range map[DBType]Dialect{
+		MYSQL:        &mysqlDialect{StandardDialect{"mysql"}, "new"},
+		MARIADB:      &mariadbDialect{mysqlDialect{StandardDialect{"mariadb"}, ""}},
+		TIDB:         &mysqlDialect{StandardDialect{"tidb"}, ""},
+		OCEANBASE:    &mysqlDialect{StandardDialect{"oceanbase"}, ""},
+		POSTGRESQL:   &postgresDialect{StandardDialect{"postgresql"}, true},
+		GREENPLUM:    &postgresDialect{StandardDialect{"greenplum"}, true},
+		OPENGAUSS:    &postgresDialect{StandardDialect{"opengauss"}, true},
+		ENTERPRISEDB: &postgresDialect{StandardDialect{"enterprisedb"}, false},
+		COCKROACHDB:  &postgresDialect{StandardDialect{"cockroachdb"}, false},
+		SQLITE:       &sqliteDialect{limitDialect{StandardDialect{"sqlite"}}},
+		ORACLE:       &oracleDialect{StandardDialect{"oracle"}},
+		SQLSERVER:    &sqlserverDialect{StandardDialect{"sqlserver"}},
+		DB2:          &db2Dialect{StandardDialect{"db2"}},
+		DERBY:        &derbyDialect{StandardDialect{"derby"}},
+		INFORMIX:     &informixDialect{StandardDialect{"informix"}},
+		HSQLDB:       &hsqldbDialect{limitDialect{StandardDialect{"hsqldb"}}},
+		INGRES:       &limitDialect{StandardDialect{"ingres"}},
+		VERTICA:      &limitDialect{StandardDialect{"vertica"}},
+		NETEZZA:      &limitDialect{StandardDialect{"netezza"}},
+		SAPHANA:      &hanaDialect{limitDialect{StandardDialect{"saphana"}}},
+		SYBASE:       &topDialect{StandardDialect{"sybase"}},
+		TERADATA:     &topDialect{StandardDialect{"teradata"}},
+		FIREBIRD:     &firebirdDialect{StandardDialect{"firebird"}},
+	} {
+		RegisterDialect(dbtype, d)
+	}
+}
+
+// mysqlDialect : MySQL, MariaDB, TiDB, OceanBase.
+// rowAlias is the alias of the inserted row read by the updates of an upsert, which replaces
+// the deprecated values() function since MySQL 8.0.19, and is empty for the others
+type mysqlDialect struct {
+	StandardDialect
+	rowAlias string
+}
+
+func (d *mysqlDialect) Quote(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+func (d *mysqlDialect) Limit(limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " LIMIT ? ", Args: []any{limit}}, nil
+}
+
+func (d *mysqlDialect) LimitOffset(offset, limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " LIMIT ?,? ", Args: []any{offset, limit}}, nil
+}
+
+func (d *mysqlDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	return duplicateKeyUpsert(table, columns, conflicts, d.rowAlias)
+}
+
+func (d *mysqlDialect) ParamLimit() int {
+	return 65535
+}
+
+func (d *mysqlDialect) BatchLimit() (rows int, ok bool) {
+	return 0, true
+}
+
+type mariadbDialect struct {
+	mysqlDialect
+}
+
+func (d *mariadbDialect) Lock(share, noWait, skipLocked bool) (LockClause, error) {
+	if share {
+		if noWait || skipLocked {
+			return LockClause{}, errUnsupported(d, "lock in share mode nowait")
+		}
+		return LockClause{Sql: " lock in share mode"}, nil
+	}
+	return d.StandardDialect.Lock(share, noWait, skipLocked)
+}
+
+func (d *mariadbDialect) Returning(columns []string) (output, returning string) {
+	return "", " returning " + strings.Join(columns, ",")
+}
+
+// postgresDialect : PostgreSQL, Greenplum, openGauss, EnterpriseDB, CockroachDB
+type postgresDialect struct {
+	StandardDialect
+	dollar bool
+}
+
+func (d *postgresDialect) Placeholder(index int) string {
+	if d.dollar {
+		return "$" + strconv.Itoa(index)
+	}
+	return "?"
+}
+
+func (d *postgresDialect) Limit(limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " LIMIT ? OFFSET 0 ", Args: []any{limit}}, nil
+}
+
+func (d *postgresDialect) LimitOffset(offset, limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " OFFSET ? LIMIT ? ", Args: []any{offset, limit}}, nil
+}
+
+func (d *postgresDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	return onConflictUpsert(table, columns, conflicts)
+}
+
+func (d *postgresDialect) Returning(columns []string) (output, returning string) {
+	return "", " returning " + strings.Join(columns, ",")
+}
+
+func (d *postgresDialect) ParamLimit() int {
+	return 65535
+}
+
+func (d *postgresDialect) BatchLimit() (rows int, ok bool) {
+	return 0, true
+}
+
+// limitDialect : LIMIT ? OFFSET ?, Ingres, Vertica, Netezza
+type limitDialect struct {
+	StandardDialect
+}
+
+func (d *limitDialect) Limit(limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " LIMIT ? ", Args: []any{limit}}, nil
+}
+
+func (d *limitDialect) LimitOffset(offset, limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " LIMIT ? OFFSET ? ", Args: []any{limit, offset}}, nil
+}
+
+func (d *limitDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	return "", errUnsupported(d, "upsert")
+}
+
+type hsqldbDialect struct {
+	limitDialect
+}
+
+// ParamLimit : no limit of the parameters of a statement is documented
+func (d *hsqldbDialect) ParamLimit() int {
+	return 0
+}
+
+func (d *hsqldbDialect) BatchLimit() (rows int, ok bool) {
+	return 0, true
+}
+
+type sqliteDialect struct {
+	limitDialect
+}
+
+func (d *sqliteDialect) Quote(identifier string) string {
+	return "`" + identifier + "`"
+}
+
+func (d *sqliteDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	return onConflictUpsert(table, columns, conflicts)
+}
+
+func (d *sqliteDialect) Lock(share, noWait, skipLocked bool) (LockClause, error) {
+	return LockClause{}, errUnsupported(d, "row locking")
+}
+
+func (d *sqliteDialect) Returning(columns []string) (output, returning string) {
+	return "", " returning " + strings.Join(columns, ",")
+}
+
+func (d *sqliteDialect) BatchLimit() (rows int, ok bool) {
+	return 0, true
+}
+
+// hanaDialect : SAP HANA
+type hanaDialect struct {
+	limitDialect
+}
+
+func (d *hanaDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	if _, err := upsertColumns(table, columns, conflicts); err != nil {
+		return "", err
+	}
+	return "upsert " + table + "(" + strings.Join(columns, ",") + ")values(" + marks(len(columns)) + ") with primary key", nil
+}
+
+type oracleDialect struct {
+	StandardDialect
+}
+
+func (d *oracleDialect) Placeholder(index int) string {
+	return ":v" + strconv.Itoa(index)
+}
+
+func (d *oracleDialect) Bind(index int, arg any) any {
+	return sql.Named("v"+strconv.Itoa(index), arg)
+}
+
+func (d *oracleDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	ss := make([]string, len(columns))
+	for i, c := range columns {
+		ss[i] = "? " + c
+	}
+	return mergeUpsert(table, columns, conflicts, "(select "+strings.Join(ss, ",")+" from dual) s", "")
+}
+
+// Lock : for update cannot be combined with fetch first, the rows of a paginated select are locked by rowid
+func (d *oracleDialect) Lock(share, noWait, skipLocked bool) (LockClause, error) {
+	if share {
+		return LockClause{}, errUnsupported(d, "for share")
+	}
+	return LockClause{Sql: lockClause(" for update", "", false, noWait, skipLocked), KeyColumn: "rowid"}, nil
+}
+
+func (d *oracleDialect) Returning(columns []string) (output, returning string) {
+	return "", " returning " + strings.Join(columns, ",") + " into " + marks(len(columns))
+}
+
+func (d *oracleDialect) ParamLimit() int {
+	return 65535
+}
+
+func (d *oracleDialect) BatchLimit() (rows int, ok bool) {
+	return 1000, true
+}
+
+// MultiRowInsert : insert all into ... values (...) into ... values (...) select 1 from dual
+func (d *oracleDialect) MultiRowInsert(head, row, tail string, n int) string {
+	target, ok := insertTarget(head)
+	if !ok || strings.TrimSpace(tail) != "" {
+		return ""
+	}
+	builder := strings.Builder{}
+	builder.WriteString("insert all")
+	for i := 0; i < n; i++ {
+		builder.WriteString(" into " + target + " values" + row)
+	}
+	builder.WriteString(" select 1 from dual")
+	return builder.String()
+}
+
+type sqlserverDialect struct {
+	StandardDialect
+}
+
+func (d *sqlserverDialect) Placeholder(index int) string {
+	return "@p" + strconv.Itoa(index)
+}
+
+func (d *sqlserverDialect) Quote(identifier string) string {
+	return "[" + identifier + "]"
+}
+
+// Limit : select top n, offset fetch requires an order by
+func (d *sqlserverDialect) Limit(limit int64) (LimitClause, error) {
+	return LimitClause{Top: "top " + strconv.FormatInt(limit, 10) + " "}, nil
+}
+
+func (d *sqlserverDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	ss := make([]string, len(columns))
+	for i, c := range columns {
+		ss[i] = "? as " + c
+	}
+	return mergeUpsert(table, columns, conflicts, "(select "+strings.Join(ss, ",")+") s", ";")
+}
+
+// Lock : table hints, updlock or holdlock, nowait and readpast
+func (d *sqlserverDialect) Lock(share, noWait, skipLocked bool) (LockClause, error) {
+	hint := " with (updlock, rowlock"
+	if share {
+		hint = " with (holdlock, rowlock"
+	}
+	if noWait {
+		hint = hint + ", nowait"
+	} else if skipLocked {
+		hint = hint + ", readpast"
+	}
+	return LockClause{Hint: hint + ")"}, nil
+}
+
+func (d *sqlserverDialect) Returning(columns []string) (output, returning string) {
+	ss := make([]string, len(columns))
+	for i, c := range columns {
+		ss[i] = "inserted." + c
+	}
+	return " output " + strings.Join(ss, ","), ""
+}
+
+func (d *sqlserverDialect) ParamLimit() int {
+	return 2100
+}
+
+func (d *sqlserverDialect) BatchLimit() (rows int, ok bool) {
+	return 1000, true
+}
+
+type db2Dialect struct {
+	StandardDialect
+}
+
+// Lock : for update after fetch first, share locks by isolation clause, no nowait
+func (d *db2Dialect) Lock(share, noWait, skipLocked bool) (LockClause, error) {
+	if noWait {
+		return LockClause{}, errUnsupported(d, "nowait")
+	}
+	s := " for update"
+	if share {
+		s = " with rs use and keep share locks"
+	}
+	if skipLocked {
+		s = s + " skip locked data"
+	}
+	return LockClause{Sql: s}, nil
+}
+
+func (d *db2Dialect) ParamLimit() int {
+	return 32767
+}
+
+func (d *db2Dialect) BatchLimit() (rows int, ok bool) {
+	return 0, true
+}
+
+type derbyDialect struct {
+	StandardDialect
+}
+
+func (d *derbyDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	return "", errUnsupported(d, "upsert")
+}
+
+// ParamLimit : no limit of the parameters of a statement is documented
+func (d *derbyDialect) ParamLimit() int {
+	return 0
+}
+
+func (d *derbyDialect) BatchLimit() (rows int, ok bool) {
+	return 0, true
+}
+
+// informixDialect : FETCH FIRST for limits, SKIP ... FIRST for offsets
+type informixDialect struct {
+	StandardDialect
+}
+
+func (d *informixDialect) LimitOffset(offset, limit int64) (LimitClause, error) {
+	return LimitClause{Top: "skip " + strconv.FormatInt(offset, 10) + " first " + strconv.FormatInt(limit, 10) + " "}, nil
+}
+
+// topDialect : select top n, Sybase, Teradata
+type topDialect struct {
+	StandardDialect
+}
+
+func (d *topDialect) Limit(limit int64) (LimitClause, error) {
+	return LimitClause{Top: "top " + strconv.FormatInt(limit, 10) + " "}, nil
+}
+
+func (d *topDialect) LimitOffset(offset, limit int64) (LimitClause, error) {
+	if offset > 0 {
+		return LimitClause{}, errUnsupported(d, "offset")
+	}
+	return d.Limit(limit)
+}
+
+func (d *topDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	return "", errUnsupported(d, "upsert")
+}
+
+func (d *topDialect) Lock(share, noWait, skipLocked bool) (LockClause, error) {
+	return LockClause{}, errUnsupported(d, "row locking")
+}
+
+type firebirdDialect struct {
+	StandardDialect
+}
+
+func (d *firebirdDialect) Limit(limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " ROWS ? ", Args: []any{limit}}, nil
+}
+
+func (d *firebirdDialect) LimitOffset(offset, limit int64) (LimitClause, error) {
+	return LimitClause{Sql: " ROWS ? TO ? ", Args: []any{offset + 1, offset + limit}}, nil
+}
+
+// Upsert : update or insert into ... matching (...)
+func (d *firebirdDialect) Upsert(table string, columns, conflicts []string) (string, error) {
+	if _, err := upsertColumns(table, columns, conflicts); err != nil {
+		return "", err
+	}
+	s := "update or insert into " + table + "(" + strings.Join(columns, ",") + ")values(" + marks(len(columns)) + ")"
+	if len(conflicts) > 0 {
+		s = s + " matching (" + strings.Join(conflicts, ",") + ")"
+	}
+	return s, nil
+}
+
+func (d *firebirdDialect) Lock(share, noWait, skipLocked bool) (LockClause, error) {
+	if share || noWait || skipLocked {
+		return LockClause{}, errUnsupported(d, "for share, nowait and skip locked")
+	}
+	return LockClause{Sql: " with lock"}, nil
+}
+
+func (d *firebirdDialect) Returning(columns []string) (output, returning string) {
+	return "", " returning " + strings.Join(columns, ",")
+}
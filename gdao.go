@@ -8,14 +8,18 @@
 package gdao
 
 import (
+	"context"
 	"database/sql"
 	. "github.com/donnie4w/gdao/base"
+	"iter"
 )
 
 type GStruct[P any, T any] interface {
 	Scanner
 	// UseCache use default gdaoCache or not use cache
 	UseCache(use bool)
+	// UseMultiRowBatch send the batch as multi-row inserts or not
+	UseMultiRowBatch(use bool)
 
 	// UseTransaction use specified transaction
 	UseTransaction(transaction Transaction)
@@ -28,6 +32,27 @@ type GStruct[P any, T any] interface {
 
 	MustMaster(must bool)
 
+	// UseVersion use the column as version for optimistic locking
+	UseVersion(column Column[T])
+
+	// UseUpsert upsert the rows of ExecBatch on the conflict columns instead of inserting them
+	UseUpsert(conflictColumns ...Column[T])
+
+	// UseIdentity use the column as the identity column, whose generated key is written back by insert
+	UseIdentity(column Column[T])
+
+	// UseAudit use the columns filled with the time and the actor by insert and update
+	UseAudit(audit *Audit)
+
+	// UseSoftDelete use the column to mark the deleted rows instead of deleting them
+	UseSoftDelete(softDelete *SoftDelete)
+
+	// Unscoped include the soft deleted rows in the queries
+	Unscoped() *Table[T]
+
+	// WithContext use ctx for cancellation and deadlines of the executed statements
+	WithContext(ctx context.Context) *Table[T]
+
 	// Where adds a WHERE clause to the query with one or more conditions.
 	//
 	// Parameters:
@@ -58,22 +83,74 @@ type GStruct[P any, T any] interface {
 	GroupBy(columns ...Column[T]) *Table[T]
 	// Having sql: having
 	Having(havings ...*Having[T]) *Table[T]
+	// InnerJoin sql: inner join table on
+	InnerJoin(table TableBase, on ...*Where[T]) *Table[T]
+	// LeftJoin sql: left join table on
+	LeftJoin(table TableBase, on ...*Where[T]) *Table[T]
+	// RightJoin sql: right join table on
+	RightJoin(table TableBase, on ...*Where[T]) *Table[T]
 	Limit2(offset, limit int64)
 	Limit(limit int64)
+	// ForUpdate sql: for update, lock the selected rows until the end of the transaction
+	ForUpdate() *Table[T]
+	// ForShare sql: for share, lock the selected rows in share mode until the end of the transaction
+	ForShare() *Table[T]
+	// NoWait sql: nowait, fail instead of waiting for the locked rows
+	NoWait() *Table[T]
+	// SkipLocked sql: skip locked, skip the locked rows
+	SkipLocked() *Table[T]
+	// Clone copy the query and the data of the entity
+	Clone() *Table[T]
+	// Reset clear the conditions, joins and limit of the query
+	Reset() *Table[T]
+	// Query return an immutable query starting with the conditions of the entity
+	Query() *Query[T]
 	// Selects sql:select from table and Return data slice
 	Selects(columns ...Column[T]) (_r []P, err error)
+	// SelectsIter sql:select from table and Return an iterator streaming the rows
+	SelectsIter(columns ...Column[T]) iter.Seq2[P, error]
+	// SelectsEach sql:select from table and call f for each row
+	SelectsEach(f func(P) bool, columns ...Column[T]) error
+	// SelectsJoined sql:select from table and joined tables and Return the rows scanned into every entity
+	SelectsJoined() (_r []*JoinRow[T], err error)
+	// Page sql:select from table with the limit of the page and select count(*), Return the page with the total
+	Page(pageNo, pageSize int64, columns ...Column[T]) (*Page[T], error)
+	// Count sql:select count(*) from table and Return the number of rows
+	Count() (int64, error)
+	// Exists sql:select 1 from table and Return whether a row was found
+	Exists() (bool, error)
+	// Sum sql:select sum(column) from table
+	Sum(column Column[T]) (float64, error)
+	// Avg sql:select avg(column) from table
+	Avg(column Column[T]) (float64, error)
+	// Max sql:select max(column) from table
+	Max(column Column[T]) (any, error)
+	// Min sql:select min(column) from table
+	Min(column Column[T]) (any, error)
 	// Select sql:select from table and Return first data
 	Select(columns ...Column[T]) (_r P, err error)
 	// Update sql: update
 	Update() (sql.Result, error)
 	// Insert sql: insert
 	Insert() (sql.Result, error)
-	// Delete sql: delete
+	// Upsert sql: insert or update on conflict, dialect aware
+	Upsert(conflictColumns ...Column[T]) (sql.Result, error)
+	// Delete sql: delete, or update the soft delete column
 	Delete() (sql.Result, error)
+	// HardDelete sql: delete, even with a soft delete column
+	HardDelete() (sql.Result, error)
+	// FindByKey sql: select from table where the key columns hold the values
+	FindByKey(columns []Column[T], values ...any) (_r P, err error)
+	// UpdateByKey sql: update the columns set on the entity where the key columns hold the values of the entity
+	UpdateByKey(columns ...Column[T]) (sql.Result, error)
+	// DeleteByKey sql: delete where the key columns hold the values
+	DeleteByKey(columns []Column[T], values ...any) (sql.Result, error)
 	// AddBatch sql: add data to batch sql
 	AddBatch()
 	// ExecBatch sql:database batch operation
 	ExecBatch() ([]sql.Result, error)
+	// BulkUpdate sql: update set column=case key when ... end where key in (...), one statement per chunk of rows
+	BulkUpdate(keyColumn Column[T], rows ...*T) ([]sql.Result, error)
 	//Copy object data
 	Copy(h P) P
 	// Encode Serialized object
@@ -14,14 +14,17 @@ import (
 	"github.com/donnie4w/gdao/util"
 	"log"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 )
 
 type TableBean struct {
-	TableName string
-	Fieldlist []*FieldBean
-	Fieldmap  map[string]*FieldBean
+	TableName  string
+	Fieldlist  []*FieldBean
+	Fieldmap   map[string]*FieldBean
+	PrimaryKey []*FieldBean
+	UniqueKeys [][]*FieldBean
 }
 
 func (t *TableBean) ContainTime() bool {
@@ -81,7 +84,7 @@ func up(s string) string {
 	return strings.ToUpper(trimNonLetterPrefix(s))
 }
 
-func buildstruct(dbtype, dbname, tableName, tableAlias string, packageName string, tableBean *TableBean, usetag bool) string {
+func buildstruct(dbtype, dbname, tableName, tableAlias string, packageName string, tableBean *TableBean, usetag bool, option *buildOption) string {
 	datetime := time.Now().Format(time.DateTime)
 	ua := util.ToUpperFirstLetter
 	if tableAlias == "" {
@@ -104,6 +107,11 @@ func buildstruct(dbtype, dbname, tableName, tableAlias string, packageName strin
 		}
 	}()
 
+	sqlPackage := ""
+	if len(tableBean.PrimaryKey) > 0 {
+		sqlPackage = `"database/sql"`
+	}
+
 	r := `// Copyright (c) 2024, donnie <donnie4w@gmail.com>
 // All rights reserved.
 // Use of this source code is governed by a BSD-style
@@ -118,6 +126,7 @@ func buildstruct(dbtype, dbname, tableName, tableAlias string, packageName strin
 package ` + packageName + `
 
 import (
+	` + sqlPackage + `
 	"fmt"
 	"github.com/donnie4w/gdao"
 	"github.com/donnie4w/gdao/base"
@@ -296,7 +305,24 @@ func (t *` + structName + `)init(tablename string) {`
 	}
 
 	initbody = initbody + `
-	t.Init(tablename, []base.Column[` + structName + `]{` + columns + `})
+	t.Init(tablename, []base.Column[` + structName + `]{` + columns + `}, t)`
+	if bean := fieldBean(tableBean, option.version); bean != nil {
+		initbody = initbody + `
+	t.UseVersion(t.` + up(bean.FieldName) + `)`
+	}
+	if bean := fieldBean(tableBean, option.identity); bean != nil {
+		initbody = initbody + `
+	t.UseIdentity(t.` + up(bean.FieldName) + `)`
+	}
+	if bean := fieldBean(tableBean, option.softDelete); bean != nil {
+		softDelete := "SoftDeleteFlag"
+		if goType(bean.FieldType) == "time.Time" {
+			softDelete = "SoftDeleteAt"
+		}
+		initbody = initbody + `
+	t.UseSoftDelete(gdao.` + softDelete + `("` + bean.FieldName + `"))`
+	}
+	initbody = initbody + `
 }
 `
 	r = r + initbody
@@ -337,11 +363,71 @@ func (t *` + structName + `) Decode(bs []byte) (err error) {
 		for name, bean := range m {
 			t.Scan(name, bean)
 		}
+		t.Loaded()
 	}
 	return
 }
 
 `
 	r = r + serialstr
+	r = r + keyMethods(structName, tableBean)
 	return r
 }
+
+// keyMethods returns the FindByPK, UpdateByPK and DeleteByPK methods of the primary key
+// and the FindBy methods of the unique keys, named after their columns. The unique keys holding
+// a nullable column are not read from the catalog, see setKeys
+func keyMethods(structName string, tableBean *TableBean) (r string) {
+	keyArgs := func(key []*FieldBean) (params, columns, args string) {
+		for i, bean := range key {
+			name := paramName(bean.FieldName)
+			if i > 0 {
+				params, columns, args = params+", ", columns+",", args+", "
+			}
+			params = params + name + " " + goType(bean.FieldType)
+			columns = columns + "t." + up(bean.FieldName)
+			args = args + name
+		}
+		return
+	}
+	methods := map[string]bool{}
+	if len(tableBean.PrimaryKey) > 0 {
+		params, columns, args := keyArgs(tableBean.PrimaryKey)
+		methods["FindByPK"] = true
+		r = r + `
+// FindByPK returns the row of the primary key, nil if there is none
+func (t *` + structName + `) FindByPK(` + params + `) (*` + structName + `, error) {
+	return t.FindByKey([]base.Column[` + structName + `]{` + columns + `}, ` + args + `)
+}
+
+// UpdateByPK updates the fields set on the entity since it was loaded, except the primary key, of the row of its primary key
+func (t *` + structName + `) UpdateByPK() (sql.Result, error) {
+	return t.UpdateByKey(` + columns + `)
+}
+
+// DeleteByPK deletes the row of the primary key
+func (t *` + structName + `) DeleteByPK(` + params + `) (sql.Result, error) {
+	return t.DeleteByKey([]base.Column[` + structName + `]{` + columns + `}, ` + args + `)
+}
+`
+	}
+	for _, key := range tableBean.UniqueKeys {
+		names := make([]string, len(key))
+		for i, bean := range key {
+			names[i] = up(bean.FieldName)
+		}
+		method := "FindBy" + strings.Join(names, "And")
+		if methods[method] || slices.Equal(key, tableBean.PrimaryKey) {
+			continue
+		}
+		methods[method] = true
+		params, columns, args := keyArgs(key)
+		r = r + `
+// ` + method + ` returns the row of the unique key, nil if there is none
+func (t *` + structName + `) ` + method + `(` + params + `) (*` + structName + `, error) {
+	return t.FindByKey([]base.Column[` + structName + `]{` + columns + `}, ` + args + `)
+}
+`
+	}
+	return
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdaoBuilder
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testTableBean() *TableBean {
+	tb := newTableBean()
+	tb.TableName = "orders"
+	for i, f := range []struct {
+		name string
+		typ  reflect.Type
+	}{{"tenant_id", reflect.TypeOf(int64(0))}, {"id", reflect.TypeOf(int64(0))}, {"type", reflect.TypeOf("")}, {"amount", reflect.TypeOf(float64(0))}} {
+		fb := &FieldBean{FieldName: f.name, FieldIndex: i, FieldType: f.typ}
+		tb.Fieldlist = append(tb.Fieldlist, fb)
+		tb.Fieldmap[f.name] = fb
+	}
+	tb.PrimaryKey = []*FieldBean{tb.Fieldmap["tenant_id"], tb.Fieldmap["id"]}
+	tb.UniqueKeys = [][]*FieldBean{{tb.Fieldmap["tenant_id"], tb.Fieldmap["type"]}, {tb.Fieldmap["tenant_id"], tb.Fieldmap["id"]}, {tb.Fieldmap["tenant_id"], tb.Fieldmap["type"]}}
+	return tb
+}
+
+func Test_keyMethods(t *testing.T) {
+	want := `
+// FindByPK returns the row of the primary key, nil if there is none
+func (t *Orders) FindByPK(tenant_id int64, id int64) (*Orders, error) {
+	return t.FindByKey([]base.Column[Orders]{t.TENANT_ID,t.ID}, tenant_id, id)
+}
+
+// UpdateByPK updates the fields set on the entity since it was loaded, except the primary key, of the row of its primary key
+func (t *Orders) UpdateByPK() (sql.Result, error) {
+	return t.UpdateByKey(t.TENANT_ID,t.ID)
+}
+
+// DeleteByPK deletes the row of the primary key
+func (t *Orders) DeleteByPK(tenant_id int64, id int64) (sql.Result, error) {
+	return t.DeleteByKey([]base.Column[Orders]{t.TENANT_ID,t.ID}, tenant_id, id)
+}
+
+// FindByTENANT_IDAndTYPE returns the row of the unique key, nil if there is none
+func (t *Orders) FindByTENANT_IDAndTYPE(tenant_id int64, type_ string) (*Orders, error) {
+	return t.FindByKey([]base.Column[Orders]{t.TENANT_ID,t.TYPE}, tenant_id, type_)
+}
+`
+	if s := keyMethods("Orders", testTableBean()); s != want {
+		t.Fatalf("\n got: %s\nwant: %s", s, want)
+	}
+	tb := testTableBean()
+	tb.PrimaryKey, tb.UniqueKeys = nil, nil
+	if s := keyMethods("Orders", tb); s != "" {
+		t.Fatal(s)
+	}
+}
+
+func Test_buildstruct(t *testing.T) {
+	s := buildstruct("mysql", "test", "orders", "", "dao", testTableBean(), false, newBuildOption(nil))
+	if _, err := parser.ParseFile(token.NewFileSet(), "orders.go", s, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"database/sql"`, "func (t *Orders) FindByPK(", "func (t *Orders) FindByTENANT_IDAndTYPE("} {
+		if !strings.Contains(s, want) {
+			t.Fatal(want)
+		}
+	}
+	tb := testTableBean()
+	tb.PrimaryKey = nil
+	if s = buildstruct("mysql", "test", "orders", "", "dao", tb, false, newBuildOption(nil)); strings.Contains(s, `"database/sql"`) {
+		t.Fatal(s)
+	}
+}
+
+func Test_setKeys(t *testing.T) {
+	tb := testTableBean()
+	tb.PrimaryKey, tb.UniqueKeys = nil, nil
+	setKeys(tb, []keyColumn{
+		{key: "PRIMARY", column: "tenant_id", primary: true},
+		{key: "PRIMARY", column: "id", primary: true},
+		{key: "uk_type", column: "tenant_id"},
+		{key: "uk_type", column: "type"},
+		{key: "uk_amount", column: "tenant_id"},
+		{key: "uk_amount", column: "amount", nullable: true},
+		{key: "uk_missing", column: "missing"},
+	})
+	if len(tb.PrimaryKey) != 2 || tb.PrimaryKey[0] != tb.Fieldmap["tenant_id"] || tb.PrimaryKey[1] != tb.Fieldmap["id"] {
+		t.Fatal(tb.PrimaryKey)
+	}
+	if len(tb.UniqueKeys) != 1 || len(tb.UniqueKeys[0]) != 2 || tb.UniqueKeys[0][1] != tb.Fieldmap["type"] {
+		t.Fatal("the unique keys holding a nullable or unknown column are not skipped", tb.UniqueKeys)
+	}
+}
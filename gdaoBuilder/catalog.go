@@ -0,0 +1,188 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdaoBuilder
+
+import (
+	"database/sql"
+	"slices"
+	"strings"
+)
+
+// readTableBean returns the columns and the keys of the table, the options set the keys
+// that are not read from the catalog of the database
+func readTableBean(tableName, dbType string, db *sql.DB, o *buildOption) (tb *TableBean, err error) {
+	if tb, err = GetTableBean(tableName, db); err != nil {
+		return
+	}
+	if err = o.check(tb); err != nil {
+		return
+	}
+	readKeys(dbType, tb, db)
+	if len(o.primaryKey) > 0 {
+		tb.PrimaryKey = make([]*FieldBean, len(o.primaryKey))
+		for i, column := range o.primaryKey {
+			tb.PrimaryKey[i] = fieldBean(tb, column)
+		}
+	}
+	if o.identity == "" {
+		o.identity = identityColumn(dbType, tableName, db)
+	}
+	return
+}
+
+// identitySql returns the query of the auto-increment or identity column of the table in the catalog of the database
+func identitySql(dbType, table string) string {
+	t := literal(table)
+	switch strings.ToLower(dbType) {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+		return "select column_name from information_schema.columns where table_schema=database() and table_name=" + t + " and extra like '%auto_increment%'"
+	case "postgresql", "greenplum", "opengauss", "enterprisedb", "cockroachdb":
+		return "select column_name from information_schema.columns where table_schema=current_schema() and table_name=" + t + " and (is_identity='YES' or column_default like 'nextval(%')"
+	case "sqlite":
+		return "select name from pragma_table_info(" + t + ") where pk=1 and lower(type)='integer' and (select count(*) from pragma_table_info(" + t + ") where pk>0)=1"
+	case "oracle":
+		return "select column_name from user_tab_identity_cols where table_name=upper(" + t + ")"
+	case "sqlserver":
+		return "select name from sys.identity_columns where object_id=object_id(" + t + ")"
+	case "db2":
+		return "select colname from syscat.columns where tabschema=current schema and tabname=upper(" + t + ") and identity='Y'"
+	case "firebird":
+		return "select trim(rdb$field_name) from rdb$relation_fields where rdb$relation_name=upper(" + t + ") and rdb$identity_type is not null"
+	}
+	return ""
+}
+
+// keySql returns the query of the columns of the primary key and of the unique keys of the table in the catalog
+// of the database: the name of the key, the name of the column, 1 for the primary key and 1 for a nullable column,
+// in the order of the columns
+func keySql(dbType, table string) string {
+	t := literal(table)
+	switch strings.ToLower(dbType) {
+	case "mysql", "mariadb", "tidb", "oceanbase":
+		return "select index_name, column_name, case when index_name='PRIMARY' then 1 else 0 end, case when nullable='YES' then 1 else 0 end" +
+			" from information_schema.statistics where table_schema=database() and table_name=" + t + " and non_unique=0 order by index_name, seq_in_index"
+	case "postgresql", "greenplum", "opengauss", "enterprisedb", "cockroachdb":
+		return "select i.relname, a.attname, case when x.indisprimary then 1 else 0 end, case when a.attnotnull then 0 else 1 end from pg_catalog.pg_index x" +
+			" join pg_catalog.pg_class i on i.oid=x.indexrelid join pg_catalog.pg_attribute a on a.attrelid=x.indrelid and a.attnum=any(x.indkey)" +
+			" where x.indrelid=to_regclass(" + t + ") and x.indisunique and x.indpred is null and x.indexprs is null" +
+			" order by i.relname, array_position(x.indkey::int2[], a.attnum)"
+	case "sqlite":
+		return "select k, c, p, z from (select 'primary' k, name c, 1 p, 0 z, pk n from pragma_table_info(" + t + ") where pk>0" +
+			" union all select l.name, i.name, 0, 1-(select t.\"notnull\" from pragma_table_info(" + t + ") t where t.name=i.name), i.seqno" +
+			" from pragma_index_list(" + t + ") l join pragma_index_info(l.name) i" +
+			" where l.\"unique\"=1 and l.origin<>'pk' and l.partial=0) order by k, n"
+	case "oracle":
+		return "select c.constraint_name, cc.column_name, case when c.constraint_type='P' then 1 else 0 end, case when tc.nullable='Y' then 1 else 0 end" +
+			" from all_constraints c join all_cons_columns cc on cc.owner=c.owner and cc.constraint_name=c.constraint_name" +
+			" join all_tab_columns tc on tc.owner=cc.owner and tc.table_name=cc.table_name and tc.column_name=cc.column_name" +
+			" where c.owner=sys_context('userenv','current_schema') and c.table_name=upper(" + t + ") and c.constraint_type in ('P','U')" +
+			" order by c.constraint_name, cc.position"
+	case "sqlserver":
+		return "select i.name, c.name, cast(i.is_primary_key as int), cast(c.is_nullable as int) from sys.indexes i" +
+			" join sys.index_columns ic on ic.object_id=i.object_id and ic.index_id=i.index_id" +
+			" join sys.columns c on c.object_id=ic.object_id and c.column_id=ic.column_id" +
+			" where i.object_id=object_id(" + t + ") and i.is_unique=1 and i.has_filter=0 and ic.is_included_column=0" +
+			" order by i.name, ic.key_ordinal"
+	case "db2":
+		return "select i.indname, c.colname, case when i.uniquerule='P' then 1 else 0 end, case when col.nulls='Y' then 1 else 0 end from syscat.indexes i" +
+			" join syscat.indexcoluse c on c.indschema=i.indschema and c.indname=i.indname" +
+			" join syscat.columns col on col.tabschema=i.tabschema and col.tabname=i.tabname and col.colname=c.colname" +
+			" where i.tabschema=current schema and i.tabname=upper(" + t + ") and i.uniquerule in ('P','U')" +
+			" order by i.indname, c.colseq"
+	case "firebird":
+		return "select trim(rc.rdb$constraint_name), trim(s.rdb$field_name), case when rc.rdb$constraint_type='PRIMARY KEY' then 1 else 0 end," +
+			" case when coalesce(rf.rdb$null_flag, f.rdb$null_flag, 0)=1 then 0 else 1 end" +
+			" from rdb$relation_constraints rc join rdb$index_segments s on s.rdb$index_name=rc.rdb$index_name" +
+			" join rdb$relation_fields rf on rf.rdb$relation_name=rc.rdb$relation_name and rf.rdb$field_name=s.rdb$field_name" +
+			" join rdb$fields f on f.rdb$field_name=rf.rdb$field_source" +
+			" where rc.rdb$relation_name=upper(" + t + ") and rc.rdb$constraint_type in ('PRIMARY KEY','UNIQUE')" +
+			" order by 1, s.rdb$field_position"
+	}
+	return ""
+}
+
+// keyColumn is a column of a key of the table read from the catalog of the database
+type keyColumn struct {
+	key      string
+	column   string
+	primary  bool
+	nullable bool
+}
+
+// readKeys sets the primary key and the unique keys of the table read from the catalog of the database,
+// the keys are left empty if they cannot be read
+func readKeys(dbType string, tb *TableBean, db *sql.DB) {
+	s := keySql(dbType, tb.TableName)
+	if s == "" {
+		return
+	}
+	rows, err := db.Query(s)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	columns := make([]keyColumn, 0)
+	for rows.Next() {
+		var c keyColumn
+		var primary, nullable int
+		if rows.Scan(&c.key, &c.column, &primary, &nullable) != nil {
+			return
+		}
+		c.primary, c.nullable = primary == 1, nullable == 1
+		columns = append(columns, c)
+	}
+	if rows.Err() == nil {
+		setKeys(tb, columns)
+	}
+}
+
+// setKeys sets the primary key and the unique keys of the table from the columns of its keys.
+// The unique keys holding a nullable column are skipped: a null does not match a row with =,
+// and several rows may hold it, so the key does not find a single row.
+func setKeys(tb *TableBean, columns []keyColumn) {
+	names := make([]string, 0)
+	keys := make(map[string][]*FieldBean)
+	primary := ""
+	nullable := make(map[string]bool)
+	for _, c := range columns {
+		if _, ok := keys[c.key]; !ok {
+			names = append(names, c.key)
+		}
+		keys[c.key] = append(keys[c.key], fieldBean(tb, c.column))
+		if c.primary {
+			primary = c.key
+		} else if c.nullable {
+			nullable[c.key] = true
+		}
+	}
+	for _, name := range names {
+		key := keys[name]
+		if slices.Contains(key, nil) {
+			continue
+		}
+		if name == primary {
+			tb.PrimaryKey = key
+		} else if !nullable[name] {
+			tb.UniqueKeys = append(tb.UniqueKeys, key)
+		}
+	}
+}
+
+// identityColumn returns the auto-increment or identity column of the table,
+// "" if there is none or if it cannot be read from the catalog of the database
+func identityColumn(dbType, table string, db *sql.DB) (column string) {
+	if s := identitySql(dbType, table); s != "" {
+		db.QueryRow(s).Scan(&column)
+	}
+	return
+}
+
+// literal returns the string literal of s
+func literal(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
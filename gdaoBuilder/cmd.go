@@ -22,6 +22,7 @@ import (
 // - dbName: The name of the database to connect to.
 // - packageName: The name of the Go package where the generated entity class will reside.
 // - db: An open database connection.
+// - options: Options of the generated entity class, such as WithVersion, WithSoftDelete, WithIdentity and WithPrimaryKey.
 //
 // Returns:
 // - err: An error if the gdao builder fails, nil otherwise.
@@ -36,8 +37,8 @@ import (
 // defer db.Close()
 // sourceCode := gdaoBuilder.Build("employees", "mysql", "my_database", "dao", db)
 // fmt.Println(sourceCode)
-func Build(tableName, dbType, dbName, packageName string, db *sql.DB) (err error) {
-	return BuildWithAlias(tableName, tableName, dbType, dbName, packageName, db)
+func Build(tableName, dbType, dbName, packageName string, db *sql.DB, options ...Option) (err error) {
+	return BuildWithAlias(tableName, tableName, dbType, dbName, packageName, db, options...)
 }
 
 // BuildWithAlias creates a source code string for a standardized gdao entity class.
@@ -49,6 +50,7 @@ func Build(tableName, dbType, dbName, packageName string, db *sql.DB) (err error
 // - dbName: The name of the database to connect to.
 // - packageName: The name of the Go package where the generated entity class will reside.
 // - db: An open database connection.
+// - options: Options of the generated entity class, such as WithVersion, WithSoftDelete, WithIdentity and WithPrimaryKey.
 //
 // Returns:
 // - err: An error if the gdao builder fails, nil otherwise.
@@ -63,8 +65,8 @@ func Build(tableName, dbType, dbName, packageName string, db *sql.DB) (err error
 // defer db.Close()
 // sourceCode := gdaoBuilder.BuildWithAlias("employees", "", "mysql", "my_database", "dao", db)
 // fmt.Println(sourceCode)
-func BuildWithAlias(tableName, tableAlias, dbType, dbName, packageName string, db *sql.DB) (err error) {
-	return BuildDirWithAlias("", tableName, tableAlias, dbType, dbName, packageName, db)
+func BuildWithAlias(tableName, tableAlias, dbType, dbName, packageName string, db *sql.DB, options ...Option) (err error) {
+	return BuildDirWithAlias("", tableName, tableAlias, dbType, dbName, packageName, db, options...)
 }
 
 // BuildDir creates a source code string for a standardized gdao entity class.
@@ -76,6 +78,7 @@ func BuildWithAlias(tableName, tableAlias, dbType, dbName, packageName string, d
 // - dbName: The name of the database to connect to.
 // - packageName: The name of the Go package where the generated entity class will reside.
 // - db: An open database connection.
+// - options: Options of the generated entity class, such as WithVersion, WithSoftDelete, WithIdentity and WithPrimaryKey.
 //
 // Returns:
 // - err: An error if the gdao builder fails, nil otherwise.
@@ -90,8 +93,8 @@ func BuildWithAlias(tableName, tableAlias, dbType, dbName, packageName string, d
 // defer db.Close()
 // sourceCode := gdaoBuilder.BuildDir("/usr/local/gdao", "employees", "mysql", "my_database", "dao", db)
 // fmt.Println(sourceCode)
-func BuildDir(dir, tableName, dbType, dbName, packageName string, db *sql.DB) (err error) {
-	return BuildDirWithAlias(dir, tableName, tableName, dbType, dbName, packageName, db)
+func BuildDir(dir, tableName, dbType, dbName, packageName string, db *sql.DB, options ...Option) (err error) {
+	return BuildDirWithAlias(dir, tableName, tableName, dbType, dbName, packageName, db, options...)
 }
 
 // BuildDirWithAlias creates a source code string for a standardized gdao entity class.
@@ -104,6 +107,7 @@ func BuildDir(dir, tableName, dbType, dbName, packageName string, db *sql.DB) (e
 // - dbName: The name of the database to connect to.
 // - packageName: The name of the Go package where the generated entity class will reside.
 // - db: An open database connection.
+// - options: Options of the generated entity class, such as WithVersion, WithSoftDelete, WithIdentity and WithPrimaryKey.
 //
 // Returns:
 // - err: An error if the gdao builder fails, nil otherwise.
@@ -118,13 +122,14 @@ func BuildDir(dir, tableName, dbType, dbName, packageName string, db *sql.DB) (e
 // defer db.Close()
 // sourceCode := gdaoBuilder.BuildDirWithAlias("/usr/local/gdao", "employees", "", "mysql", "my_database", "dao", db)
 // fmt.Println(sourceCode)
-func BuildDirWithAlias(dir, tableName, tableAlias, dbType, dbName, packageName string, db *sql.DB) (err error) {
+func BuildDirWithAlias(dir, tableName, tableAlias, dbType, dbName, packageName string, db *sql.DB, options ...Option) (err error) {
 	var tb *TableBean
-	if tb, err = GetTableBean(tableName, db); err == nil {
+	option := newBuildOption(options)
+	if tb, err = readTableBean(tableName, dbType, db, option); err == nil {
 		if tableAlias == "" {
 			tableAlias = tableName
 		}
-		if structstr := buildstruct(dbType, dbName, tableName, tableAlias, packageName, tb, false); structstr != "" {
+		if structstr := buildstruct(dbType, dbName, tableName, tableAlias, packageName, tb, false, option); structstr != "" {
 			fileName := filepath.Join(packageName, tableAlias) + ".go"
 			if dir != "" {
 				fileName = filepath.Join(dir, fileName)
@@ -141,7 +146,7 @@ func BuildDirWithAlias(dir, tableName, tableAlias, dbType, dbName, packageName s
 		}
 	}
 	if err != nil {
-		log.Println("[failed to created gdao struct]", aslog(tableName, tableAlias))
+		log.Println("[failed to created gdao struct]", aslog(tableName, tableAlias), err)
 	}
 	return
 }
@@ -156,6 +161,7 @@ func BuildDirWithAlias(dir, tableName, tableAlias, dbType, dbName, packageName s
 // - dbName: The name of the database to connect to.
 // - packageName: The name of the Go package where the generated entity class will reside.
 // - db: An open database connection.
+// - options: Options of the generated entity class, such as WithVersion, WithSoftDelete, WithIdentity and WithPrimaryKey.
 //
 // Returns:
 // - err: An error if the gdao builder fails, nil otherwise.
@@ -170,13 +176,14 @@ func BuildDirWithAlias(dir, tableName, tableAlias, dbType, dbName, packageName s
 // defer db.Close()
 // sourceCode := gdaoBuilder.BuildDirWithAlias("/usr/local/gdao", "employees", "", "mysql", "my_database", "dao", db)
 // fmt.Println(sourceCode)
-func BuildDirWithAliasAndTAG(dir, tableName, tableAlias, dbType, dbName, packageName string, db *sql.DB) (err error) {
+func BuildDirWithAliasAndTAG(dir, tableName, tableAlias, dbType, dbName, packageName string, db *sql.DB, options ...Option) (err error) {
 	var tb *TableBean
-	if tb, err = GetTableBean(tableName, db); err == nil {
+	option := newBuildOption(options)
+	if tb, err = readTableBean(tableName, dbType, db, option); err == nil {
 		if tableAlias == "" {
 			tableAlias = tableName
 		}
-		if structstr := buildstruct(dbType, dbName, tableName, tableAlias, packageName, tb, true); structstr != "" {
+		if structstr := buildstruct(dbType, dbName, tableName, tableAlias, packageName, tb, true, option); structstr != "" {
 			fileName := filepath.Join(packageName, tableAlias) + ".go"
 			if dir != "" {
 				fileName = filepath.Join(dir, fileName)
@@ -193,7 +200,7 @@ func BuildDirWithAliasAndTAG(dir, tableName, tableAlias, dbType, dbName, package
 		}
 	}
 	if err != nil {
-		log.Println("[failed to created gdao struct]", aslog(tableName, tableAlias))
+		log.Println("[failed to created gdao struct]", aslog(tableName, tableAlias), err)
 	}
 	return
 }
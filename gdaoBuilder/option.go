@@ -0,0 +1,100 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdaoBuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Option configures the entity class generated by the Build functions
+type Option func(*buildOption)
+
+type buildOption struct {
+	version    string
+	softDelete string
+	identity   string
+	primaryKey []string
+}
+
+func newBuildOption(options []Option) *buildOption {
+	o := &buildOption{}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// WithVersion marks the integer column as the version of the rows for optimistic locking,
+// the generated entity class calls Table.UseVersion with it.
+//
+// Example:
+//
+//	gdaoBuilder.BuildDir("/usr/local/gdao", "employees", "mysql", "my_database", "dao", db, gdaoBuilder.WithVersion("version"))
+func WithVersion(column string) Option {
+	return func(o *buildOption) {
+		o.version = column
+	}
+}
+
+// WithSoftDelete marks the column as the soft delete column of the table, the generated entity class
+// calls Table.UseSoftDelete with it, as gdao.SoftDeleteAt for a timestamp column and gdao.SoftDeleteFlag otherwise.
+//
+// Example:
+//
+//	gdaoBuilder.BuildDir("/usr/local/gdao", "employees", "mysql", "my_database", "dao", db, gdaoBuilder.WithSoftDelete("deleted_at"))
+func WithSoftDelete(column string) Option {
+	return func(o *buildOption) {
+		o.softDelete = column
+	}
+}
+
+// WithIdentity marks the column as the auto-increment or identity column of the table, the generated entity class
+// calls Table.UseIdentity with it. Without this option the identity column is read from the catalog of the database.
+//
+// Example:
+//
+//	gdaoBuilder.BuildDir("/usr/local/gdao", "employees", "mysql", "my_database", "dao", db, gdaoBuilder.WithIdentity("id"))
+func WithIdentity(column string) Option {
+	return func(o *buildOption) {
+		o.identity = column
+	}
+}
+
+// WithPrimaryKey sets the columns of the primary key of the table, for the tables whose primary key
+// cannot be read from the catalog of the database, such as views. The generated entity class has
+// the FindByPK, UpdateByPK and DeleteByPK methods of the primary key.
+//
+// Example:
+//
+//	gdaoBuilder.BuildDir("/usr/local/gdao", "employees", "mysql", "my_database", "dao", db, gdaoBuilder.WithPrimaryKey("dept_no", "emp_no"))
+func WithPrimaryKey(columns ...string) Option {
+	return func(o *buildOption) {
+		o.primaryKey = columns
+	}
+}
+
+// check returns an error if a column of the options is not a column of the table
+func (o *buildOption) check(tb *TableBean) error {
+	for _, column := range append([]string{o.version, o.softDelete, o.identity}, o.primaryKey...) {
+		if column != "" && fieldBean(tb, column) == nil {
+			return fmt.Errorf("the column %s was not found in the table %s", column, tb.TableName)
+		}
+	}
+	return nil
+}
+
+// fieldBean returns the column of the table with the name, case-insensitively
+func fieldBean(tb *TableBean, column string) *FieldBean {
+	for _, bean := range tb.Fieldlist {
+		if strings.EqualFold(bean.FieldName, column) {
+			return bean
+		}
+	}
+	return nil
+}
@@ -10,6 +10,9 @@ package gdaoBuilder
 import (
 	"database/sql"
 	"fmt"
+	"github.com/donnie4w/gdao"
+	"github.com/donnie4w/gdao/util"
+	"go/token"
 	"reflect"
 	"strings"
 	"unicode"
@@ -154,12 +157,22 @@ func tag(dbtype, fieldname string, useTag bool) string {
 	if !useTag {
 		return fieldname
 	}
-	switch strings.ToLower(dbtype) {
-	case "mysql", "mariadb", "sqlite", "tidb", "oceanbase":
-		return "`" + fieldname + "`"
-	case "sqlserver":
-		return "[" + fieldname + "]"
-	default:
-		return `\"` + fieldname + `\"`
+	return strings.ReplaceAll(gdao.DialectByName(dbtype).Quote(fieldname), `"`, `\"`)
+}
+
+// paramName returns the name of the parameter of a generated method holding the value of the column
+func paramName(column string) string {
+	name := util.ToUpperFirstLetter(trimNonLetterPrefix(column))
+	if name == "" {
+		return "arg"
+	}
+	if name == strings.ToUpper(name) {
+		name = strings.ToLower(name)
+	} else {
+		name = strings.ToLower(name[:1]) + name[1:]
+	}
+	if name == "t" || token.IsKeyword(name) {
+		name = name + "_"
 	}
+	return name
 }
@@ -8,6 +8,7 @@
 package gdaoCache
 
 import (
+	"context"
 	"github.com/donnie4w/gdao/base"
 	"github.com/donnie4w/gdao/gdaoStruct"
 	"github.com/donnie4w/gdao/util"
@@ -188,6 +189,26 @@ func GetMapperCache(domain, namepace, id string, condition *Condition) any {
 	return gdaocache.GetMapperCache(domain, namepace, id, condition)
 }
 
+// GetCacheContext returns the cached result like GetCache, in a base.SpanCacheGet span of ctx
+func GetCacheContext(ctx context.Context, domain, cacheId string, condition *Condition) (r any) {
+	attrs := base.Attrs{base.AttrCacheDomain: domain}
+	_, end := base.StartSpan(ctx, base.SpanCacheGet, attrs)
+	r = gdaocache.GetCache(domain, cacheId, condition)
+	attrs[base.AttrCacheHit] = r != nil
+	end(nil)
+	return
+}
+
+// GetMapperCacheContext returns the cached result like GetMapperCache, in a base.SpanCacheGet span of ctx
+func GetMapperCacheContext(ctx context.Context, domain, namepace, id string, condition *Condition) (r any) {
+	attrs := base.Attrs{base.AttrCacheDomain: domain, base.AttrMapperId: namepace + "." + id}
+	_, end := base.StartSpan(ctx, base.SpanCacheGet, attrs)
+	r = gdaocache.GetMapperCache(domain, namepace, id, condition)
+	attrs[base.AttrCacheHit] = r != nil
+	end(nil)
+	return
+}
+
 func SetMapperCache(domain string, namespace, id string, condition *Condition, value any) bool {
 	return gdaocache.SetMapperCache(domain, namespace, id, condition, value)
 }
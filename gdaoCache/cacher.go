@@ -261,13 +261,16 @@ func (c *cacher) GetCache(domain, cacheId string, condition *Condition) any {
 			hashcode := condition.hash()
 			if cacheBean, b := cacheBeanMap.Get(hashcode); b {
 				if time.Now().UnixMilli()-cacheBean.timestamp-cacheHandle.expire <= 0 {
+					base.AddCounter(base.MetricCacheHits, 1, base.LabelDomain, domain)
 					return cacheBean.value
 				} else {
 					cacheBeanMap.Del(hashcode)
+					base.AddCounter(base.MetricCacheEvictions, 1, base.LabelDomain, domain)
 				}
 			}
 		}
 	}
+	base.AddCounter(base.MetricCacheMisses, 1, base.LabelDomain, domain)
 	return nil
 }
 
@@ -321,15 +324,20 @@ func (c *cacher) ticker() {
 			case <-tk.C:
 				b := memorymonitor.CheckMemoryPressure()
 				c.cacheMap.Range(func(domain string, cachehandle *CacheHandle) bool {
+					evictions := int64(0)
 					cachehandle.mm.Range(func(cacheId string, cacheBeanMap *Map[uint64, *CacheBean]) bool {
 						cacheBeanMap.Range(func(condition uint64, cb *CacheBean) bool {
 							if (b && cachehandle.storemode == SOFT) || time.Now().UnixMilli()-cachehandle.expire-cb.timestamp > 0 {
 								cacheBeanMap.Del(condition)
+								evictions++
 							}
 							return true
 						})
 						return true
 					})
+					if evictions > 0 {
+						base.AddCounter(base.MetricCacheEvictions, evictions, base.LabelDomain, domain)
+					}
 					return true
 				})
 			}
@@ -8,8 +8,11 @@
 package gdaoMapper
 
 import (
+	"context"
 	"database/sql"
+	"github.com/donnie4w/gdao"
 	"github.com/donnie4w/gdao/base"
+	"iter"
 )
 
 // GdaoMapper is the interface for the gdaoMapper module, providing methods to manage transactions and database connections.
@@ -130,6 +133,21 @@ type GdaoMapper interface {
 	//       log.Fatalf("Failed to deleteXml user: %v", err)
 	//   }
 	Delete(mapperId string, args ...any) (sql.Result, error)
+
+	// SelectBeanContext is like SelectBean but uses ctx to cancel the query or enforce its deadline.
+	SelectBeanContext(ctx context.Context, mapperId string, args ...any) *base.DataBean
+
+	// SelectBeansContext is like SelectBeans but uses ctx to cancel the query or enforce its deadline.
+	SelectBeansContext(ctx context.Context, mapperId string, args ...any) *base.DataBeans
+
+	// InsertContext is like Insert but uses ctx to cancel the statement or enforce its deadline.
+	InsertContext(ctx context.Context, mapperId string, args ...any) (sql.Result, error)
+
+	// UpdateContext is like Update but uses ctx to cancel the statement or enforce its deadline.
+	UpdateContext(ctx context.Context, mapperId string, args ...any) (sql.Result, error)
+
+	// DeleteContext is like Delete but uses ctx to cancel the statement or enforce its deadline.
+	DeleteContext(ctx context.Context, mapperId string, args ...any) (sql.Result, error)
 }
 
 var (
@@ -249,6 +267,21 @@ var (
 	//       log.Fatalf("Failed to insertXml user: %v", err)
 	//   }
 	Delete func(mapperId string, args ...any) (r sql.Result, err error)
+
+	// SelectBeanContext is like SelectBean but uses ctx to cancel the query or enforce its deadline.
+	SelectBeanContext func(ctx context.Context, mapperId string, args ...any) *base.DataBean
+
+	// SelectBeansContext is like SelectBeans but uses ctx to cancel the query or enforce its deadline.
+	SelectBeansContext func(ctx context.Context, mapperId string, args ...any) *base.DataBeans
+
+	// InsertContext is like Insert but uses ctx to cancel the statement or enforce its deadline.
+	InsertContext func(ctx context.Context, mapperId string, args ...any) (r sql.Result, err error)
+
+	// UpdateContext is like Update but uses ctx to cancel the statement or enforce its deadline.
+	UpdateContext func(ctx context.Context, mapperId string, args ...any) (r sql.Result, err error)
+
+	// DeleteContext is like Delete but uses ctx to cancel the statement or enforce its deadline.
+	DeleteContext func(ctx context.Context, mapperId string, args ...any) (r sql.Result, err error)
 )
 
 // Select executes a query based on the specified XML mapping mapper ID and returns a single row of data as an instance of the generic type T.
@@ -276,10 +309,15 @@ var (
 //	    log.Fatalf("Failed to select user: %v", err)
 //	}
 func Select[T any](mapperId string, args ...any) (*T, error) {
+	return SelectContext[T](context.Background(), mapperId, args...)
+}
+
+// SelectContext is like Select but uses ctx to cancel the query or enforce its deadline.
+func SelectContext[T any](ctx context.Context, mapperId string, args ...any) (*T, error) {
 	if len(args) == 1 {
-		return selectAny[T](mapperId, args[0])
+		return selectAny[T](ctx, mapperId, args[0])
 	}
-	return (*mapperInvoke[T])(defaultMapperHandler).SelectDirect(mapperId, args...)
+	return (*mapperInvoke[T])(defaultMapperHandler).SelectDirect(ctx, mapperId, args...)
 }
 
 // selectAny executes a query based on the specified XML mapping mapper ID and returns a single row of data as an instance of the generic type T.
@@ -309,8 +347,8 @@ func Select[T any](mapperId string, args ...any) (*T, error) {
 //	if err != nil {
 //	    log.Fatalf("Failed to select user: %v", err)
 //	}
-func selectAny[T any](mapperId string, parameter any) (*T, error) {
-	return (*mapperInvoke[T])(defaultMapperHandler).Select(mapperId, parameter)
+func selectAny[T any](ctx context.Context, mapperId string, parameter any) (*T, error) {
+	return (*mapperInvoke[T])(defaultMapperHandler).Select(ctx, mapperId, parameter)
 }
 
 // Selects executes a query based on the specified XML mapping mapper ID and returns multiple rows of data as instances of the generic type T.
@@ -338,10 +376,15 @@ func selectAny[T any](mapperId string, parameter any) (*T, error) {
 //	    log.Fatalf("Failed to select users: %v", err)
 //	}
 func Selects[T any](mapperId string, args ...any) ([]*T, error) {
+	return SelectsContext[T](context.Background(), mapperId, args...)
+}
+
+// SelectsContext is like Selects but uses ctx to cancel the query or enforce its deadline.
+func SelectsContext[T any](ctx context.Context, mapperId string, args ...any) ([]*T, error) {
 	if len(args) == 1 {
-		return selectsAny[T](mapperId, args[0])
+		return selectsAny[T](ctx, mapperId, args[0])
 	}
-	return (*mapperInvoke[T])(defaultMapperHandler).SelectsDirect(mapperId, args...)
+	return (*mapperInvoke[T])(defaultMapperHandler).SelectsDirect(ctx, mapperId, args...)
 }
 
 // selectsAny executes a query based on the specified XML mapping mapper ID and returns multiple rows of data as instances of the generic type T.
@@ -369,6 +412,98 @@ func Selects[T any](mapperId string, args ...any) ([]*T, error) {
 //	if err != nil {
 //	    log.Fatalf("Failed to select users: %v", err)
 //	}
-func selectsAny[T any](mapperId string, parameter any) ([]*T, error) {
-	return (*mapperInvoke[T])(defaultMapperHandler).Selects(mapperId, parameter)
+func selectsAny[T any](ctx context.Context, mapperId string, parameter any) ([]*T, error) {
+	return (*mapperInvoke[T])(defaultMapperHandler).Selects(ctx, mapperId, parameter)
+}
+
+// SelectPage executes a query based on the specified XML mapping mapper ID and returns the page pageNo of pageSize rows
+// as instances of the generic type T, with the total number of rows of the query.
+//
+// Parameters:
+//
+//	T: A generic type parameter representing the type of the data to be returned.
+//	mapperId: The ID of the CRUD operation within the XML mapping namespace.
+//	pageNo: The number of the page, starting with 1.
+//	pageSize: The maximum number of rows of a page.
+//	args: Variable length argument list, which corresponds to placeholder arguments of mapperId.
+//
+// Returns:
+//
+//	A pointer to a gdao.Page holding the rows, the total number of rows, the page count and whether there is a next page.
+//
+// Description:
+//
+//	The total is read by a count query wrapping the statement of mapperId without its order by clause,
+//	and the rows by the statement with the limit of the page written for the database type.
+//	The statement of mapperId should therefore have no limit of its own.
+//
+// Example:
+//
+//	// Assuming "com.example.mappers.users" is the namespace in the XML mapping files
+//	// And "getUsersByAge" is the ID of the CRUD operation within the namespace
+//	page, err := gdaoMapper.SelectPage[dao.User]("com.example.mappers.users.getUsersByAge", 1, 20, 18)
+//	if err != nil {
+//	    log.Fatalf("Failed to select users: %v", err)
+//	}
+//	fmt.Println(page.Total, page.PageCount, page.Rows)
+func SelectPage[T any](mapperId string, pageNo, pageSize int64, args ...any) (*gdao.Page[T], error) {
+	return SelectPageContext[T](context.Background(), mapperId, pageNo, pageSize, args...)
+}
+
+// SelectPageContext is like SelectPage but uses ctx to cancel the queries or enforce their deadline.
+func SelectPageContext[T any](ctx context.Context, mapperId string, pageNo, pageSize int64, args ...any) (*gdao.Page[T], error) {
+	return (*mapperInvoke[T])(defaultMapperHandler).SelectPage(ctx, mapperId, pageNo, pageSize, args...)
+}
+
+// SelectsIter executes a query based on the specified XML mapping mapper ID and returns an iterator over the rows as instances of the generic type T.
+//
+// Parameters:
+//
+//	T: A generic type parameter representing the type of the data to be returned.
+//	mapperId: The ID of the CRUD operation within the XML mapping namespace.
+//	args: Variable length argument list, which corresponds to placeholder arguments of mapperId.
+//
+// Returns:
+//
+//	An iterator yielding a pointer to an instance of type T for each row, or an error that ends the iteration.
+//
+// Description:
+//
+//	Unlike Selects, the rows are not collected into a slice: the underlying *sql.Rows is kept open and every row is
+//	converted when the loop advances. The rows are closed when the loop finishes or breaks early. The cache is not used.
+//
+// Example:
+//
+//	for user, err := range gdaoMapper.SelectsIter[dao.User]("com.example.mappers.users.getUsersByLimit", 10) {
+//	    if err != nil {
+//	        log.Fatalf("Failed to select users: %v", err)
+//	    }
+//	    fmt.Println(user)
+//	}
+func SelectsIter[T any](mapperId string, args ...any) iter.Seq2[*T, error] {
+	return SelectsIterContext[T](context.Background(), mapperId, args...)
+}
+
+// SelectsIterContext is like SelectsIter but uses ctx to cancel the query or enforce its deadline.
+func SelectsIterContext[T any](ctx context.Context, mapperId string, args ...any) iter.Seq2[*T, error] {
+	return (*mapperInvoke[T])(defaultMapperHandler).SelectsIter(ctx, mapperId, args...)
+}
+
+// SelectsEach executes a query based on the specified XML mapping mapper ID and calls f for each row as it is read.
+// The iteration stops when f returns false. It returns the first error encountered.
+func SelectsEach[T any](mapperId string, f func(*T) bool, args ...any) error {
+	return SelectsEachContext[T](context.Background(), mapperId, f, args...)
+}
+
+// SelectsEachContext is like SelectsEach but uses ctx to cancel the query or enforce its deadline.
+func SelectsEachContext[T any](ctx context.Context, mapperId string, f func(*T) bool, args ...any) error {
+	for v, err := range SelectsIterContext[T](ctx, mapperId, args...) {
+		if err != nil {
+			return err
+		}
+		if !f(v) {
+			break
+		}
+	}
+	return nil
 }
@@ -8,6 +8,7 @@
 package gdaoMapper
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/donnie4w/gdao"
@@ -49,7 +50,7 @@ func (t *mapperHandler) SetAutocommit(autocommit bool) (err error) {
 		if dbHandle := t.getDBhandle("", "", false); dbHandle != nil {
 			t.transaction, err = dbHandle.GetTransaction()
 		} else {
-			fmt.Errorf("no data source was found")
+			err = fmt.Errorf("no data source was found")
 		}
 	} else {
 		t.transaction = nil
@@ -90,12 +91,16 @@ func (t *mapperHandler) getDBhandle(namespace, id string, queryType bool) (dbhan
 }
 
 func (t *mapperHandler) SelectBean(mapperId string, args ...any) (r *DataBean) {
+	return t.SelectBeanContext(context.Background(), mapperId, args...)
+}
+
+func (t *mapperHandler) SelectBeanContext(ctx context.Context, mapperId string, args ...any) (r *DataBean) {
 	if len(args) == 1 {
-		return t.selectBean(mapperId, args[0])
+		return t.selectBean(ctx, mapperId, args[0])
 	}
 	var pb *paramBean
 	var err error
-	if pb, args, err = t.parseParameter2(mapperId, args...); err != nil {
+	if pb, args, err = t.parseParameter2(ctx, mapperId, args...); err != nil {
 		r = &DataBean{}
 		r.SetError(err)
 		return
@@ -103,14 +108,14 @@ func (t *mapperHandler) SelectBean(mapperId string, args ...any) (r *DataBean) {
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nSelectBeanDirect SQL["+pb.sql+"]ARGS", args)
 	}
-	return t._selectBean(mapperId, pb, args...)
+	return t._selectBean(ctx, mapperId, pb, args...)
 }
 
-func (t *mapperHandler) selectBean(mapperId string, parameter any) (r *DataBean) {
+func (t *mapperHandler) selectBean(ctx context.Context, mapperId string, parameter any) (r *DataBean) {
 	var pb *paramBean
 	var args []any
 	var err error
-	if pb, args, err = t.parseParameter(mapperId, parameter); err != nil {
+	if pb, args, err = t.parseParameter(ctx, mapperId, parameter); err != nil {
 		r = &DataBean{}
 		r.SetError(err)
 		return
@@ -118,23 +123,23 @@ func (t *mapperHandler) selectBean(mapperId string, parameter any) (r *DataBean)
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nSelectBean SQL["+pb.sql+"]ARGS", args)
 	}
-	return t._selectBean(mapperId, pb, args...)
+	return t._selectBean(ctx, mapperId, pb, args...)
 }
 
-func (t *mapperHandler) _selectBean(mapperId string, pb *paramBean, args ...any) (r *DataBean) {
+func (t *mapperHandler) _selectBean(ctx context.Context, mapperId string, pb *paramBean, args ...any) (r *DataBean) {
 	domain := gdaoCache.GetMapperDomain(pb.namespace, pb.id)
 	isCache := domain != ""
 	var condition *gdaoCache.Condition
 	if isCache {
 		condition = gdaoCache.NewCondition("*DataBean", pb.sql, args...)
-		if result := gdaoCache.GetMapperCache(domain, pb.namespace, pb.id, condition); result != nil {
+		if result := gdaoCache.GetMapperCacheContext(ctx, domain, pb.namespace, pb.id, condition); result != nil {
 			if Logger.IsVaild {
 				Logger.Debug("[GET CACHE]["+pb.sql+"]", args)
 			}
 			return result.(*DataBean)
 		}
 	}
-	if r = t.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryBean(pb.sql, args...); r.GetError() == nil {
+	if r = t.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryBeanContext(ctx, pb.sql, args...); r.GetError() == nil {
 		if isCache {
 			gdaoCache.SetMapperCache(domain, pb.namespace, pb.id, condition, r)
 			if Logger.IsVaild {
@@ -146,12 +151,16 @@ func (t *mapperHandler) _selectBean(mapperId string, pb *paramBean, args ...any)
 }
 
 func (t *mapperHandler) SelectBeans(mapperId string, args ...any) *DataBeans {
+	return t.SelectBeansContext(context.Background(), mapperId, args...)
+}
+
+func (t *mapperHandler) SelectBeansContext(ctx context.Context, mapperId string, args ...any) *DataBeans {
 	if len(args) == 1 {
-		return t.selectBeans(mapperId, args[0])
+		return t.selectBeans(ctx, mapperId, args[0])
 	}
 	var pb *paramBean
 	var err error
-	if pb, args, err = t.parseParameter2(mapperId, args...); err != nil {
+	if pb, args, err = t.parseParameter2(ctx, mapperId, args...); err != nil {
 		r := &DataBeans{}
 		r.SetError(err)
 		return r
@@ -159,14 +168,14 @@ func (t *mapperHandler) SelectBeans(mapperId string, args ...any) *DataBeans {
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nSelectsBean SQL["+pb.sql+"]ARGS", args)
 	}
-	return t._selectBeans(mapperId, pb, args...)
+	return t._selectBeans(ctx, mapperId, pb, args...)
 }
 
-func (t *mapperHandler) selectBeans(mapperId string, parameter any) *DataBeans {
+func (t *mapperHandler) selectBeans(ctx context.Context, mapperId string, parameter any) *DataBeans {
 	var pb *paramBean
 	var args []any
 	var err error
-	if pb, args, err = t.parseParameter(mapperId, parameter); err != nil {
+	if pb, args, err = t.parseParameter(ctx, mapperId, parameter); err != nil {
 		r := &DataBeans{}
 		r.SetError(err)
 		return r
@@ -174,23 +183,23 @@ func (t *mapperHandler) selectBeans(mapperId string, parameter any) *DataBeans {
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nSelectBeans SQL["+pb.sql+"]ARGS", args)
 	}
-	return t._selectBeans(mapperId, pb, args...)
+	return t._selectBeans(ctx, mapperId, pb, args...)
 }
 
-func (t *mapperHandler) _selectBeans(mapperId string, pb *paramBean, args ...any) (r *DataBeans) {
+func (t *mapperHandler) _selectBeans(ctx context.Context, mapperId string, pb *paramBean, args ...any) (r *DataBeans) {
 	domain := gdaoCache.GetMapperDomain(pb.namespace, pb.id)
 	isCache := domain != ""
 	var condition *gdaoCache.Condition
 	if isCache {
 		condition = gdaoCache.NewCondition("[]*DataBean", pb.sql, args...)
-		if result := gdaoCache.GetMapperCache(domain, pb.namespace, pb.id, condition); result != nil {
+		if result := gdaoCache.GetMapperCacheContext(ctx, domain, pb.namespace, pb.id, condition); result != nil {
 			if Logger.IsVaild {
 				Logger.Debug("[GET CACHE]["+pb.sql+"]", args)
 			}
 			return result.(*DataBeans)
 		}
 	}
-	if r = t.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryBeans(pb.sql, args...); r.GetError() == nil && r.Len() > 0 {
+	if r = t.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryBeansContext(ctx, pb.sql, args...); r.GetError() == nil && r.Len() > 0 {
 		if isCache {
 			gdaoCache.SetMapperCache(domain, pb.namespace, pb.id, condition, r)
 			if Logger.IsVaild {
@@ -202,84 +211,97 @@ func (t *mapperHandler) _selectBeans(mapperId string, pb *paramBean, args ...any
 }
 
 func (t *mapperHandler) Insert(mapperId string, args ...any) (r sql.Result, err error) {
+	return t.InsertContext(context.Background(), mapperId, args...)
+}
+
+func (t *mapperHandler) InsertContext(ctx context.Context, mapperId string, args ...any) (r sql.Result, err error) {
 	if len(args) == 1 {
-		return t.insert(mapperId, args[0])
+		return t.insert(ctx, mapperId, args[0])
 	}
 	var pb *paramBean
-	if pb, args, err = t.parseParameter2(mapperId, args...); err != nil {
+	if pb, args, err = t.parseParameter2(ctx, mapperId, args...); err != nil {
 		return r, err
 	}
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nInsertDirect SQL["+pb.sql+"]ARGS", args)
 	}
-	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdate(pb.sql, args...)
+	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdateContext(ctx, pb.sql, args...)
 }
 
-func (t *mapperHandler) insert(mapperId string, parameter any) (r sql.Result, err error) {
+func (t *mapperHandler) insert(ctx context.Context, mapperId string, parameter any) (r sql.Result, err error) {
 	var pb *paramBean
 	var args []any
-	if pb, args, err = t.parseParameter(mapperId, parameter); err != nil {
+	if pb, args, err = t.parseParameter(ctx, mapperId, parameter); err != nil {
 		return r, err
 	}
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nInsert SQL["+pb.sql+"]ARGS", args)
 	}
-	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdate(pb.sql, args...)
+	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdateContext(ctx, pb.sql, args...)
 }
 
 func (t *mapperHandler) Update(mapperId string, args ...any) (r sql.Result, err error) {
+	return t.UpdateContext(context.Background(), mapperId, args...)
+}
+
+func (t *mapperHandler) UpdateContext(ctx context.Context, mapperId string, args ...any) (r sql.Result, err error) {
 	if len(args) == 1 {
-		return t.update(mapperId, args[0])
+		return t.update(ctx, mapperId, args[0])
 	}
 	var pb *paramBean
-	if pb, args, err = t.parseParameter2(mapperId, args...); err != nil {
+	if pb, args, err = t.parseParameter2(ctx, mapperId, args...); err != nil {
 		return r, err
 	}
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nUpdateDirect SQL["+pb.sql+"]ARGS", args)
 	}
-	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdate(pb.sql, args...)
+	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdateContext(ctx, pb.sql, args...)
 }
 
-func (t *mapperHandler) update(mapperId string, parameter any) (r sql.Result, err error) {
+func (t *mapperHandler) update(ctx context.Context, mapperId string, parameter any) (r sql.Result, err error) {
 	var pb *paramBean
 	var args []any
-	if pb, args, err = t.parseParameter(mapperId, parameter); err != nil {
+	if pb, args, err = t.parseParameter(ctx, mapperId, parameter); err != nil {
 		return r, err
 	}
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nUpdate SQL["+pb.sql+"]ARGS", args)
 	}
-	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdate(pb.sql, args...)
+	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdateContext(ctx, pb.sql, args...)
 }
 
 func (t *mapperHandler) Delete(mapperId string, args ...any) (r sql.Result, err error) {
+	return t.DeleteContext(context.Background(), mapperId, args...)
+}
+
+func (t *mapperHandler) DeleteContext(ctx context.Context, mapperId string, args ...any) (r sql.Result, err error) {
 	if len(args) == 1 {
-		return t.delete(mapperId, args[0])
+		return t.delete(ctx, mapperId, args[0])
 	}
 	var pb *paramBean
-	if pb, args, err = t.parseParameter2(mapperId, args...); err != nil {
+	if pb, args, err = t.parseParameter2(ctx, mapperId, args...); err != nil {
 		return r, err
 	}
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nDeleteDirect SQL["+pb.sql+"]ARGS", args)
 	}
-	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdate(pb.sql, args...)
+	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdateContext(ctx, pb.sql, args...)
 }
 
-func (t *mapperHandler) delete(mapperId string, parameter any) (r sql.Result, err error) {
+func (t *mapperHandler) delete(ctx context.Context, mapperId string, parameter any) (r sql.Result, err error) {
 	var pb *paramBean
 	var args []any
-	if pb, args, err = t.parseParameter(mapperId, parameter); err != nil {
+	if pb, args, err = t.parseParameter(ctx, mapperId, parameter); err != nil {
 		return r, err
 	}
 	if Logger.IsVaild {
 		Logger.Debug("[Mapper Id] "+mapperId+" \nDelete SQL["+pb.sql+"]ARGS", args)
 	}
-	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdate(pb.sql, args...)
+	return t.getDBhandle(pb.namespace, pb.id, false).ExecuteUpdateContext(ctx, pb.sql, args...)
 }
 
-func (t *mapperHandler) parseParameter(mapperId string, parameter any) (pb *paramBean, args []any, err error) {
+func (t *mapperHandler) parseParameter(ctx context.Context, mapperId string, parameter any) (pb *paramBean, args []any, err error) {
+	defer resolveSpan(ctx, mapperId, &pb, &err)()
 	defer util.Recover(&err)
 	var ok bool
 	if pb, ok = mapperparser.getParamBean(mapperId); !ok {
@@ -295,7 +317,8 @@ func (t *mapperHandler) parseParameter(mapperId string, parameter any) (pb *para
 	return
 }
 
-func (t *mapperHandler) parseParameter2(mapperId string, _args ...any) (pb *paramBean, args []any, err error) {
+func (t *mapperHandler) parseParameter2(ctx context.Context, mapperId string, _args ...any) (pb *paramBean, args []any, err error) {
+	defer resolveSpan(ctx, mapperId, &pb, &err)()
 	defer util.Recover(&err)
 	var ok bool
 	if pb, ok = mapperparser.getParamBean(mapperId); !ok {
@@ -311,6 +334,19 @@ func (t *mapperHandler) parseParameter2(mapperId string, _args ...any) (pb *para
 	return
 }
 
+// resolveSpan starts the SpanMapperResolve span of the mapper id, and returns the function ending it
+// with the statement and the error of the resolution
+func resolveSpan(ctx context.Context, mapperId string, pb **paramBean, err *error) func() {
+	attrs := Attrs{AttrMapperId: mapperId}
+	_, end := StartSpan(ctx, SpanMapperResolve, attrs)
+	return func() {
+		if *pb != nil {
+			attrs[AttrDBStatement] = (*pb).sql
+		}
+		end(*err)
+	}
+}
+
 var defaultMapperHandler *mapperHandler
 
 // NewInstance create a GdaoMapper Object
@@ -334,4 +370,10 @@ func init() {
 	Insert = defaultMapperHandler.Insert
 	Update = defaultMapperHandler.Update
 	Delete = defaultMapperHandler.Delete
+
+	SelectBeanContext = defaultMapperHandler.SelectBeanContext
+	SelectBeansContext = defaultMapperHandler.SelectBeansContext
+	InsertContext = defaultMapperHandler.InsertContext
+	UpdateContext = defaultMapperHandler.UpdateContext
+	DeleteContext = defaultMapperHandler.DeleteContext
 }
@@ -8,17 +8,20 @@
 package gdaoMapper
 
 import (
+	"context"
 	"fmt"
+	"github.com/donnie4w/gdao"
 	"github.com/donnie4w/gdao/base"
 	"github.com/donnie4w/gdao/gdaoCache"
 	"github.com/donnie4w/gdao/util"
+	"iter"
 	"reflect"
 	"time"
 )
 
 type mapperInvoke[T any] mapperHandler
 
-func (m *mapperInvoke[T]) SelectDirect(mapperId string, args ...any) (r *T, er error) {
+func (m *mapperInvoke[T]) SelectDirect(ctx context.Context, mapperId string, args ...any) (r *T, er error) {
 	mh := (*mapperHandler)(m)
 	if pb, ok := mapperparser.getParamBean(mapperId); !ok {
 		return nil, fmt.Errorf("Mapper Id not found [%s]", mapperId)
@@ -26,33 +29,33 @@ func (m *mapperInvoke[T]) SelectDirect(mapperId string, args ...any) (r *T, er e
 		if base.Logger.IsVaild {
 			base.Logger.Debug("[Mapper Id] "+mapperId+" \nSelectDirect SQL["+pb.sql+"]ARGS", args)
 		}
-		if pb, args, er = mh.parseParameter2(mapperId, args...); er == nil {
-			return _select[T](mh, pb, args...)
+		if pb, args, er = mh.parseParameter2(ctx, mapperId, args...); er == nil {
+			return _select[T](ctx, mh, pb, args...)
 		}
 	}
 	return
 }
 
-func (m *mapperInvoke[T]) Select(mapperId string, parameter any) (r *T, err error) {
+func (m *mapperInvoke[T]) Select(ctx context.Context, mapperId string, parameter any) (r *T, err error) {
 	var pb *paramBean
 	var args []any
 	mh := (*mapperHandler)(m)
-	if pb, args, err = mh.parseParameter(mapperId, parameter); err != nil {
+	if pb, args, err = mh.parseParameter(ctx, mapperId, parameter); err != nil {
 		return r, err
 	}
 	if base.Logger.IsVaild {
 		base.Logger.Debug("[Mapper Id] "+mapperId+" \nSelect SQL["+pb.sql+"]ARGS", args)
 	}
-	return _select[T](mh, pb, args...)
+	return _select[T](ctx, mh, pb, args...)
 }
 
-func _select[T any](mh *mapperHandler, pb *paramBean, args ...any) (r *T, err error) {
+func _select[T any](ctx context.Context, mh *mapperHandler, pb *paramBean, args ...any) (r *T, err error) {
 	domain := gdaoCache.GetMapperDomain(pb.namespace, pb.id)
 	isCache := domain != ""
 	var condition *gdaoCache.Condition
 	if isCache {
 		condition = gdaoCache.NewCondition("*"+util.Classname[T](), pb.sql, args...)
-		if result := gdaoCache.GetMapperCache(domain, pb.namespace, pb.id, condition); result != nil {
+		if result := gdaoCache.GetMapperCacheContext(ctx, domain, pb.namespace, pb.id, condition); result != nil {
 			if base.Logger.IsVaild {
 				base.Logger.Debug("[GET CACHE]["+pb.sql+"]", args)
 			}
@@ -60,7 +63,7 @@ func _select[T any](mh *mapperHandler, pb *paramBean, args ...any) (r *T, err er
 		}
 	}
 	var databean *base.DataBean
-	if databean = mh.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryBean(pb.sql, args...); databean.GetError() == nil && databean.Len() > 0 {
+	if databean = mh.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryBeanContext(ctx, pb.sql, args...); databean.GetError() == nil && databean.Len() > 0 {
 		if isDBType(pb.outputType) {
 			r, err = toT[T](databean)
 		}
@@ -85,7 +88,7 @@ func _select[T any](mh *mapperHandler, pb *paramBean, args ...any) (r *T, err er
 	return
 }
 
-func (m *mapperInvoke[T]) SelectsDirect(mapperId string, args ...any) (r []*T, er error) {
+func (m *mapperInvoke[T]) SelectsDirect(ctx context.Context, mapperId string, args ...any) (r []*T, er error) {
 	mh := (*mapperHandler)(m)
 	if pb, ok := mapperparser.getParamBean(mapperId); !ok {
 		return nil, fmt.Errorf("Mapper Id not found [%s]", mapperId)
@@ -93,40 +96,40 @@ func (m *mapperInvoke[T]) SelectsDirect(mapperId string, args ...any) (r []*T, e
 		if base.Logger.IsVaild {
 			base.Logger.Debug("[Mapper Id] "+mapperId+" \nSelectsDirect SQL["+pb.sql+"]ARGS", args)
 		}
-		if pb, args, er = mh.parseParameter2(mapperId, args...); er == nil {
-			return selects[T](mh, pb, args...)
+		if pb, args, er = mh.parseParameter2(ctx, mapperId, args...); er == nil {
+			return selects[T](ctx, mh, pb, args...)
 		}
 	}
 	return
 }
 
-func (m *mapperInvoke[T]) Selects(mapperId string, parameter any) (r []*T, err error) {
+func (m *mapperInvoke[T]) Selects(ctx context.Context, mapperId string, parameter any) (r []*T, err error) {
 	var pb *paramBean
 	var args []any
 	mh := (*mapperHandler)(m)
-	if pb, args, err = mh.parseParameter(mapperId, parameter); err != nil {
+	if pb, args, err = mh.parseParameter(ctx, mapperId, parameter); err != nil {
 		return r, err
 	}
 	if base.Logger.IsVaild {
 		base.Logger.Debug("[Mapper Id] "+mapperId+" \nSelects SQL["+pb.sql+"]ARGS", args)
 	}
-	return selects[T](mh, pb, args...)
+	return selects[T](ctx, mh, pb, args...)
 }
 
-func selects[T any](mh *mapperHandler, pb *paramBean, args ...any) (r []*T, err error) {
+func selects[T any](ctx context.Context, mh *mapperHandler, pb *paramBean, args ...any) (r []*T, err error) {
 	domain := gdaoCache.GetMapperDomain(pb.namespace, pb.id)
 	isCache := domain != ""
 	var condition *gdaoCache.Condition
 	if isCache {
 		condition = gdaoCache.NewCondition("[]*"+util.Classname[T](), pb.sql, args...)
-		if result := gdaoCache.GetMapperCache(domain, pb.namespace, pb.id, condition); result != nil {
+		if result := gdaoCache.GetMapperCacheContext(ctx, domain, pb.namespace, pb.id, condition); result != nil {
 			if base.Logger.IsVaild {
 				base.Logger.Debug("[GET CACHE]["+pb.sql+"]", args)
 			}
 			return result.([]*T), nil
 		}
 	}
-	if databeans := mh.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryBeans(pb.sql, args...); databeans.GetError() == nil && databeans.Len() > 0 {
+	if databeans := mh.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryBeansContext(ctx, pb.sql, args...); databeans.GetError() == nil && databeans.Len() > 0 {
 		r = make([]*T, 0)
 		if isDBType(pb.outputType) {
 			for _, databean := range databeans.Beans {
@@ -168,6 +171,93 @@ func selects[T any](mh *mapperHandler, pb *paramBean, args ...any) (r []*T, err
 	return
 }
 
+func (m *mapperInvoke[T]) SelectPage(ctx context.Context, mapperId string, pageNo, pageSize int64, args ...any) (r *gdao.Page[T], err error) {
+	var pb *paramBean
+	mh := (*mapperHandler)(m)
+	if len(args) == 1 {
+		pb, args, err = mh.parseParameter(ctx, mapperId, args[0])
+	} else {
+		pb, args, err = mh.parseParameter2(ctx, mapperId, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	dbhandle := mh.getDBhandle(pb.namespace, pb.id, true)
+	pagesql, limitArgs, err := gdao.PageSql(dbhandle.GetDBType(), pb.sql, pageNo, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	countsql := gdao.CountSql(pb.sql)
+	if base.Logger.IsVaild {
+		base.Logger.Debug("[Mapper Id] "+mapperId+" \nSelectPage COUNT SQL["+countsql+"]ARGS", args)
+	}
+	bean := dbhandle.ExecuteQueryBeanContext(ctx, countsql, append([]any(nil), args...)...)
+	if err = bean.GetError(); err != nil {
+		return nil, err
+	}
+	total := bean.ToInt64()
+	var rows []*T
+	if (max(pageNo, 1)-1)*pageSize < total {
+		pagepb := newParamBean2(pb.namespace, pb.id, pagesql, pb.inputType, pb.outputType, pb.sqltype)
+		if base.Logger.IsVaild {
+			base.Logger.Debug("[Mapper Id] "+mapperId+" \nSelectPage SQL["+pagesql+"]ARGS", args)
+		}
+		if rows, err = selects[T](ctx, mh, pagepb, append(args, limitArgs...)...); err != nil {
+			return nil, err
+		}
+	}
+	return gdao.NewPage(rows, total, pageNo, pageSize), nil
+}
+
+func (m *mapperInvoke[T]) SelectsIter(ctx context.Context, mapperId string, args ...any) iter.Seq2[*T, error] {
+	var pb *paramBean
+	var err error
+	mh := (*mapperHandler)(m)
+	if len(args) == 1 {
+		pb, args, err = mh.parseParameter(ctx, mapperId, args[0])
+	} else {
+		pb, args, err = mh.parseParameter2(ctx, mapperId, args...)
+	}
+	if err != nil {
+		return func(yield func(*T, error) bool) {
+			yield(nil, err)
+		}
+	}
+	if base.Logger.IsVaild {
+		base.Logger.Debug("[Mapper Id] "+mapperId+" \nSelectsIter SQL["+pb.sql+"]ARGS", args)
+	}
+	return selectsIter[T](ctx, mh, pb, args...)
+}
+
+func selectsIter[T any](ctx context.Context, mh *mapperHandler, pb *paramBean, args ...any) iter.Seq2[*T, error] {
+	seq := mh.getDBhandle(pb.namespace, pb.id, true).ExecuteQueryIter(ctx, pb.sql, args...)
+	dbtype := pb.outputType != "" && isDBType(pb.outputType)
+	return func(yield func(*T, error) bool) {
+		for databean, err := range seq {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			var v *T
+			if dbtype {
+				v, err = toT[T](databean)
+			} else {
+				v = new(T)
+				if err = databean.Scan(v); err != nil {
+					v, err = toT[T](databean)
+				}
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
 func toT[T any](databean *base.DataBean) (r *T, err error) {
 	defer util.Recover(&err)
 	if databean == nil {
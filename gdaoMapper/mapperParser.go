@@ -100,6 +100,9 @@ func (m *mapperParser) parseMapper(xmlpath string) (err error) {
 
 	for _, crudNode := range mapper.CrudNodes {
 		pb := newParamBean(mapper.Namespace, crudNode.ID, crudNode.XMLName.Local, crudNode.Query, crudNode.ParameterType, crudNode.ResultType)
+		if pb.softDelete = newSoftDelete(crudNode); pb.softDelete != nil {
+			pb.sql = pb.softDelete.apply(pb.sqltype, pb.sql)
+		}
 		m.mapperAdd(mapper.Namespace, crudNode.ID, pb)
 		if node := sqlnode(crudNode); node != nil {
 			pb.sqlNode = node
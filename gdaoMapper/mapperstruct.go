@@ -38,12 +38,15 @@ type mapper struct {
 }
 
 type CrudNode struct {
-	XMLName       xml.Name     `xml:""`
-	ID            string       `xml:"id,attr"`
-	ResultType    string       `xml:"resultType,attr"`
-	ParameterType string       `xml:"parameterType,attr,omitempty"`
-	Query         string       `xml:",chardata"`
-	Dynamics      []DynamicXml `xml:",any"`
+	XMLName         xml.Name     `xml:""`
+	ID              string       `xml:"id,attr"`
+	ResultType      string       `xml:"resultType,attr"`
+	ParameterType   string       `xml:"parameterType,attr,omitempty"`
+	SoftDelete      string       `xml:"softDelete,attr,omitempty"`
+	DeletedValue    string       `xml:"deletedValue,attr,omitempty"`
+	NotDeletedValue string       `xml:"notDeletedValue,attr,omitempty"`
+	Query           string       `xml:",chardata"`
+	Dynamics        []DynamicXml `xml:",any"`
 }
 
 type DynamicXml struct {
@@ -25,6 +25,7 @@ type paramBean struct {
 	inputType      string
 	outputType     string
 	sqlNode        sqlNode
+	softDelete     *softDelete
 }
 
 func newParamBean2(namespace, id, sql, inputType, outputType string, sqltype sqlType) *paramBean {
@@ -334,7 +335,7 @@ func (p *paramBean) parseSqlNode(parameter any) (r *paramBean, params []any) {
 	if ac.params != nil {
 		params = ac.params
 	}
-	return newParamBean2(p.namespace, p.id, ac.GetSql(), p.inputType, p.outputType, p.sqltype), params
+	return newParamBean2(p.namespace, p.id, p.softDelete.apply(p.sqltype, ac.GetSql()), p.inputType, p.outputType, p.sqltype), params
 }
 
 func (p *paramBean) parseSqlNode2(args ...any) (r *paramBean, params []any) {
@@ -342,5 +343,5 @@ func (p *paramBean) parseSqlNode2(args ...any) (r *paramBean, params []any) {
 	if ac.params != nil {
 		params = ac.params
 	}
-	return newParamBean2(p.namespace, p.id, ac.GetSql(), p.inputType, p.outputType, p.sqltype), params
+	return newParamBean2(p.namespace, p.id, p.softDelete.apply(p.sqltype, ac.GetSql()), p.inputType, p.outputType, p.sqltype), params
 }
@@ -0,0 +1,70 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdaoMapper
+
+import (
+	"github.com/donnie4w/gdao/base"
+	"github.com/donnie4w/gdao/util"
+)
+
+// softDelete is the soft delete column of a select or delete element of the XML mapping, set by its attributes:
+//
+//	softDelete: the name of the column
+//	deletedValue: the SQL value set by the delete element, current_timestamp if not set
+//	notDeletedValue: the SQL value of the rows not deleted, null if not set
+//
+// Example:
+//
+//	<select id="selectHstest" resultType="Hstest" softDelete="deleted_at">
+//	    select * from hstest where age > #{age}
+//	</select>
+//	<delete id="deleteHstest" parameterType="int64" softDelete="is_deleted" deletedValue="1" notDeletedValue="0">
+//	    delete from hstest where id = #{id}
+//	</delete>
+//
+// The select statement is executed as select * from hstest where (age > ?) and deleted_at is null,
+// the delete statement as update hstest set is_deleted=1 where id = ?
+type softDelete struct {
+	column     string
+	deleted    string
+	notDeleted string
+}
+
+func newSoftDelete(node CrudNode) *softDelete {
+	if node.SoftDelete == "" {
+		return nil
+	}
+	return &softDelete{column: node.SoftDelete, deleted: node.DeletedValue, notDeleted: node.NotDeletedValue}
+}
+
+// apply returns the select statement sqlstr excluding the deleted rows,
+// or the delete statement sqlstr rewritten as an update of the soft delete column
+func (s *softDelete) apply(sqltype sqlType, sqlstr string) string {
+	if s == nil {
+		return sqlstr
+	}
+	switch sqltype {
+	case _SELECT:
+		if s.notDeleted == "" {
+			return util.AddCondition(sqlstr, s.column+" is null")
+		}
+		return util.AddCondition(sqlstr, s.column+"="+s.notDeleted)
+	case _DELETE:
+		deleted := s.deleted
+		if deleted == "" {
+			deleted = "current_timestamp"
+		}
+		if r, ok := util.DeleteToUpdate(sqlstr, s.column+"="+deleted); ok {
+			return r
+		}
+		if base.Logger.IsVaild {
+			base.Logger.Warn("[SOFT DELETE] unsupported delete statement [" + sqlstr + "]")
+		}
+	}
+	return sqlstr
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	. "github.com/donnie4w/gdao/base"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// hstest is the entity class of the tests, as written by gdaoBuilder
+type hstest struct {
+	Table[hstest]
+
+	ID       *Field[hstest]
+	NAME     *Field[hstest]
+	AGE      *Field[hstest]
+	VERSION  *Field[hstest]
+	_ID      *int64
+	_NAME    *string
+	_AGE     *int64
+	_VERSION *int64
+}
+
+var _hstest_ID = &Field[hstest]{FieldName: "id"}
+var _hstest_NAME = &Field[hstest]{FieldName: "name"}
+var _hstest_AGE = &Field[hstest]{FieldName: "age"}
+var _hstest_VERSION = &Field[hstest]{FieldName: "version"}
+
+func (u *hstest) GetId() (_r int64) {
+	if u._ID != nil {
+		_r = *u._ID
+	}
+	return
+}
+
+func (u *hstest) SetId(arg int64) *hstest {
+	u.Put0(u.ID.FieldName, arg)
+	u._ID = &arg
+	return u
+}
+
+func (u *hstest) GetName() (_r string) {
+	if u._NAME != nil {
+		_r = *u._NAME
+	}
+	return
+}
+
+func (u *hstest) SetName(arg string) *hstest {
+	u.Put0(u.NAME.FieldName, arg)
+	u._NAME = &arg
+	return u
+}
+
+func (u *hstest) SetAge(arg int64) *hstest {
+	u.Put0(u.AGE.FieldName, arg)
+	u._AGE = &arg
+	return u
+}
+
+func (u *hstest) SetVersion(arg int64) *hstest {
+	u.Put0(u.VERSION.FieldName, arg)
+	u._VERSION = &arg
+	return u
+}
+
+func (u *hstest) Scan(fieldname string, value any) {
+	switch fieldname {
+	case "id":
+		u.SetId(AsInt64(value))
+	case "name":
+		u.SetName(AsString(value))
+	case "age":
+		u.SetAge(AsInt64(value))
+	case "version":
+		u.SetVersion(AsInt64(value))
+	}
+}
+
+// hstestHook is called with the name of the lifecycle hook by the hooks of hstest, if it is set
+var hstestHook func(name string) error
+
+func (u *hstest) hook(name string) error {
+	if hstestHook != nil {
+		return hstestHook(name)
+	}
+	return nil
+}
+
+func (u *hstest) BeforeInsert() error { return u.hook("BeforeInsert") }
+func (u *hstest) AfterInsert() error  { return u.hook("AfterInsert") }
+func (u *hstest) BeforeUpdate() error { return u.hook("BeforeUpdate") }
+func (u *hstest) AfterUpdate() error  { return u.hook("AfterUpdate") }
+func (u *hstest) BeforeDelete() error { return u.hook("BeforeDelete") }
+func (u *hstest) AfterDelete() error  { return u.hook("AfterDelete") }
+func (u *hstest) AfterFind() error    { return u.hook("AfterFind") }
+
+func (t *hstest) ToGdao() {
+	t.init("hstest")
+}
+
+func (t *hstest) init(tablename string) {
+	t.ID = _hstest_ID
+	t.NAME = _hstest_NAME
+	t.AGE = _hstest_AGE
+	t.VERSION = _hstest_VERSION
+	t.Init(tablename, []Column[hstest]{t.ID, t.NAME, t.AGE, t.VERSION}, t)
+}
+
+func newHstest() *hstest {
+	r := &hstest{}
+	r.init("hstest")
+	return r
+}
+
+// testDriver is a database/sql driver recording the statements it is given,
+// and answering the queries with the rows set on it
+type testDriver struct {
+	mu       sync.Mutex
+	log      []testStatement
+	columns  []string
+	rows     [][]driver.Value
+	closed   int
+	insertId int64
+	affected int64
+	queryErr error
+	execErr  func(sql string, args []driver.Value) error
+}
+
+var testdriver = &testDriver{}
+
+func init() {
+	sql.Register("gdaotest", testdriver)
+}
+
+// useTestDB binds gdao to a new database of the test driver, whose queries return the rows of the columns
+func useTestDB(t *testing.T, dbtype DBType, columns []string, rows ...[]driver.Value) *testDriver {
+	t.Helper()
+	testdriver.mu.Lock()
+	testdriver.log, testdriver.columns, testdriver.rows, testdriver.closed = nil, columns, rows, 0
+	testdriver.insertId, testdriver.affected, testdriver.queryErr, testdriver.execErr = 0, 1, nil, nil
+	testdriver.mu.Unlock()
+	db, err := sql.Open("gdaotest", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	Init(db, dbtype)
+	return testdriver
+}
+
+// testStatement is a statement executed by the test driver, or begin, commit and rollback
+type testStatement struct {
+	sql  string
+	args []driver.Value
+}
+
+func (s testStatement) String() string {
+	if s.args == nil {
+		return s.sql
+	}
+	return fmt.Sprint(s.sql, s.args)
+}
+
+// statements returns the statements recorded since the last call
+func (d *testDriver) statements() []testStatement {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	r := d.log
+	d.log = nil
+	return r
+}
+
+// closedRows returns the number of the rows closed since useTestDB
+func (d *testDriver) closedRows() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closed
+}
+
+func (d *testDriver) record(sql string, args []driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, testStatement{sql, args})
+}
+
+func (d *testDriver) Open(string) (driver.Conn, error) {
+	return &testConn{d}, nil
+}
+
+type testConn struct{ d *testDriver }
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return &testStmt{c.d, query}, nil
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	c.d.record("begin", nil)
+	return &testTx{c.d}, nil
+}
+
+type testTx struct{ d *testDriver }
+
+func (tx *testTx) Commit() error {
+	tx.d.record("commit", nil)
+	return nil
+}
+
+func (tx *testTx) Rollback() error {
+	tx.d.record("rollback", nil)
+	return nil
+}
+
+type testStmt struct {
+	d     *testDriver
+	query string
+}
+
+func (s *testStmt) Close() error {
+	return nil
+}
+
+func (s *testStmt) NumInput() int {
+	return -1
+}
+
+func (s *testStmt) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.record(s.query, args)
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if s.d.execErr != nil {
+		if err := s.d.execErr(s.query, args); err != nil {
+			return nil, err
+		}
+	}
+	return testResult{s.d.insertId, s.d.affected}, nil
+}
+
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.record(s.query, args)
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if s.d.queryErr != nil {
+		return nil, s.d.queryErr
+	}
+	return &testRows{d: s.d, columns: s.d.columns, rows: s.d.rows}, nil
+}
+
+func (s *testStmt) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.Exec(testValues(args))
+}
+
+func (s *testStmt) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.Query(testValues(args))
+}
+
+func testValues(args []driver.NamedValue) []driver.Value {
+	r := make([]driver.Value, len(args))
+	for i, arg := range args {
+		r[i] = arg.Value
+	}
+	return r
+}
+
+type testResult struct{ insertId, affected int64 }
+
+func (r testResult) LastInsertId() (int64, error) {
+	return r.insertId, nil
+}
+
+func (r testResult) RowsAffected() (int64, error) {
+	return r.affected, nil
+}
+
+type testRows struct {
+	d       *testDriver
+	columns []string
+	rows    [][]driver.Value
+	i       int
+}
+
+func (r *testRows) Columns() []string {
+	return r.columns
+}
+
+func (r *testRows) Close() error {
+	r.d.mu.Lock()
+	defer r.d.mu.Unlock()
+	r.d.closed++
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.i])
+	r.i++
+	return nil
+}
+
+// checkStatements fails the test unless the statements recorded since the last call are the expected ones
+func checkStatements(t *testing.T, d *testDriver, want ...string) {
+	t.Helper()
+	s := d.statements()
+	if len(s) != len(want) {
+		t.Fatalf("statements:\n got: %v\nwant: %v", s, want)
+	}
+	for i, w := range want {
+		if s[i].String() != w {
+			t.Fatalf("statement %d:\n got: %s\nwant: %s", i, s[i], w)
+		}
+	}
+}
+
+// checkSql fails the test unless the statement and its arguments are the expected ones
+func checkSql(t *testing.T, sqlstr string, args []any, wantSql string, wantArgs ...any) {
+	t.Helper()
+	if sqlstr != wantSql {
+		t.Fatalf("sql:\n got: %s\nwant: %s", sqlstr, wantSql)
+	}
+	if len(args) != len(wantArgs) || (len(args) > 0 && !reflect.DeepEqual(args, wantArgs)) {
+		t.Fatalf("args of %s:\n got: %v\nwant: %v", sqlstr, args, wantArgs)
+	}
+}
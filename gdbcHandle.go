@@ -8,8 +8,10 @@
 package gdao
 
 import (
+	"context"
 	"database/sql"
 	"github.com/donnie4w/gdao/base"
+	"iter"
 )
 
 type gdbcHandle interface {
@@ -17,6 +19,11 @@ type gdbcHandle interface {
 	ExecuteQueryBean(sqlstr string, args ...any) *base.DataBean
 	ExecuteUpdate(sqlstr string, args ...any) (sql.Result, error)
 	ExecuteBatch(sqlstr string, args [][]any) (r []sql.Result, err error)
+	ExecuteQueryBeansContext(ctx context.Context, sqlstr string, args ...any) *base.DataBeans
+	ExecuteQueryBeanContext(ctx context.Context, sqlstr string, args ...any) *base.DataBean
+	ExecuteUpdateContext(ctx context.Context, sqlstr string, args ...any) (sql.Result, error)
+	ExecuteBatchContext(ctx context.Context, sqlstr string, args [][]any) (r []sql.Result, err error)
+	ExecuteQueryIter(ctx context.Context, sqlstr string, args ...any) iter.Seq2[*base.DataBean, error]
 	GetDBType() base.DBType
 	GetDB() *sql.DB
 	Close() error
@@ -40,20 +47,34 @@ func (g *gdbcHandler) GetDB() *sql.DB {
 }
 
 func (g *gdbcHandler) ExecuteQueryBeans(sqlstr string, args ...any) (r *base.DataBeans) {
+	return g.ExecuteQueryBeansContext(context.Background(), sqlstr, args...)
+}
+
+func (g *gdbcHandler) ExecuteQueryBean(sqlstr string, args ...any) (r *base.DataBean) {
+	return g.ExecuteQueryBeanContext(context.Background(), sqlstr, args...)
+}
+
+func (g *gdbcHandler) ExecuteUpdate(sqlstr string, args ...any) (sql.Result, error) {
+	return g.ExecuteUpdateContext(context.Background(), sqlstr, args...)
+}
+
+func (g *gdbcHandler) ExecuteBatch(sqlstr string, args [][]any) ([]sql.Result, error) {
+	return g.ExecuteBatchContext(context.Background(), sqlstr, args)
+}
+
+func (g *gdbcHandler) ExecuteQueryBeansContext(ctx context.Context, sqlstr string, args ...any) (r *base.DataBeans) {
 	r = &base.DataBeans{}
-	sqlstr = parseSql(g.DBType, sqlstr, args)
-	if dbs, err := stmtExec.executeQueryBeans(g.TX, g.DB, sqlstr, args...); err == nil {
-		r.Beans = dbs
+	if rs, err := g.execute(&Statement{Kind: StmtQueryBeans, Ctx: ctx, Sql: sqlstr, Args: args}); err == nil {
+		r.Beans = rs.Beans
 	} else {
 		r.SetError(err)
 	}
 	return
 }
 
-func (g *gdbcHandler) ExecuteQueryBean(sqlstr string, args ...any) (r *base.DataBean) {
-	sqlstr = parseSql(g.DBType, sqlstr, args)
-	if db, err := stmtExec.executeQueryBean(g.TX, g.DB, sqlstr, args...); err == nil {
-		return db
+func (g *gdbcHandler) ExecuteQueryBeanContext(ctx context.Context, sqlstr string, args ...any) (r *base.DataBean) {
+	if rs, err := g.execute(&Statement{Kind: StmtQueryBean, Ctx: ctx, Sql: sqlstr, Args: args}); err == nil && rs.Bean != nil {
+		return rs.Bean
 	} else {
 		r = &base.DataBean{}
 		r.SetError(err)
@@ -61,14 +82,46 @@ func (g *gdbcHandler) ExecuteQueryBean(sqlstr string, args ...any) (r *base.Data
 	return
 }
 
-func (g *gdbcHandler) ExecuteUpdate(sqlstr string, args ...any) (sql.Result, error) {
-	sqlstr = parseSql(g.DBType, sqlstr, args)
-	return stmtExec.executeUpdate(g.TX, g.DB, sqlstr, args...)
+func (g *gdbcHandler) ExecuteUpdateContext(ctx context.Context, sqlstr string, args ...any) (sql.Result, error) {
+	rs, err := g.execute(&Statement{Kind: StmtUpdate, Ctx: ctx, Sql: sqlstr, Args: args})
+	if err != nil {
+		return nil, err
+	}
+	return rs.Result, nil
 }
 
-func (g *gdbcHandler) ExecuteBatch(sqlstr string, args [][]any) ([]sql.Result, error) {
-	sqlstr = parseSql(g.DBType, sqlstr, args)
-	return executeBatch(g.TX, g.DB, sqlstr, args)
+func (g *gdbcHandler) ExecuteBatchContext(ctx context.Context, sqlstr string, args [][]any) ([]sql.Result, error) {
+	rs, err := g.execute(&Statement{Kind: StmtBatch, Ctx: ctx, Sql: sqlstr, BatchArgs: args})
+	if err != nil {
+		return nil, err
+	}
+	return rs.Results, nil
+}
+
+func (g *gdbcHandler) ExecuteQueryIter(ctx context.Context, sqlstr string, args ...any) iter.Seq2[*base.DataBean, error] {
+	rs, err := g.execute(&Statement{Kind: StmtQueryIter, Ctx: ctx, Sql: sqlstr, Args: args})
+	if err != nil {
+		return func(yield func(*base.DataBean, error) bool) {
+			yield(nil, err)
+		}
+	}
+	if rs.Iter == nil {
+		return func(yield func(*base.DataBean, error) bool) {}
+	}
+	return rs.Iter
+}
+
+// execute executes the statement with the handle through the interceptors, see Use
+func (g *gdbcHandler) execute(stmt *Statement) (*Result, error) {
+	stmt.DBType, stmt.DataSource, stmt.Tx, stmt.gdbc = g.DBType, dataSourceName(g.DB), g.TX != nil, g
+	if stmt.Ctx == nil {
+		stmt.Ctx = context.Background()
+	}
+	rs, err := intercept(stmt)
+	if rs == nil && err == nil {
+		rs = &Result{}
+	}
+	return rs, err
 }
 
 func (g *gdbcHandler) Close() error {
@@ -1,6 +1,6 @@
 module github.com/donnie4w/gdao
 
-go 1.22.4
+go 1.23
 
 require (
 	github.com/donnie4w/gofer v0.1.7
@@ -0,0 +1,102 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var errHook = errors.New("hook failed")
+
+// useHooks records the hooks called on hstest, the hook named fail returns errHook
+func useHooks(t *testing.T, fail string) *[]string {
+	calls := new([]string)
+	hstestHook = func(name string) error {
+		*calls = append(*calls, name)
+		if name == fail {
+			return errHook
+		}
+		return nil
+	}
+	t.Cleanup(func() { hstestHook = nil })
+	return calls
+}
+
+func Test_hook(t *testing.T) {
+	d := useTestDB(t, MYSQL, nil)
+	calls := useHooks(t, "")
+	hs := newHstest()
+	hs.SetId(1)
+	if _, err := hs.Insert(); err != nil {
+		t.Fatal(err)
+	}
+	hs.Where(hs.ID.EQ(1))
+	if _, err := hs.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hs.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hs.HardDelete(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"BeforeInsert", "AfterInsert", "BeforeUpdate", "AfterUpdate", "BeforeDelete", "AfterDelete", "BeforeDelete", "AfterDelete"}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Fatal(*calls)
+	}
+	if s := d.statements(); len(s) != 4 {
+		t.Fatal(s)
+	}
+
+	for _, fail := range []string{"BeforeInsert", "BeforeUpdate", "BeforeDelete"} {
+		calls := useHooks(t, fail)
+		hs := newHstest()
+		hs.SetId(1)
+		var err error
+		switch fail {
+		case "BeforeInsert":
+			_, err = hs.Insert()
+		case "BeforeUpdate":
+			_, err = hs.Update()
+		case "BeforeDelete":
+			_, err = hs.Delete()
+		}
+		if !errors.Is(err, errHook) || !reflect.DeepEqual(*calls, []string{fail}) {
+			t.Fatal(fail, err, *calls)
+		}
+		checkStatements(t, d)
+	}
+
+	useHooks(t, "AfterInsert")
+	hs = newHstest()
+	hs.SetId(1)
+	if _, err := hs.Insert(); !errors.Is(err, errHook) {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, "insert  into hstest(id )values(?)[1]")
+}
+
+func Test_hookAfterFind(t *testing.T) {
+	useTestDB(t, MYSQL, []string{"id"}, []driver.Value{int64(1)}, []driver.Value{int64(2)})
+	calls := useHooks(t, "")
+	rows, err := newHstest().Selects()
+	if err != nil || len(rows) != 2 {
+		t.Fatal(rows, err)
+	}
+	if !reflect.DeepEqual(*calls, []string{"AfterFind", "AfterFind"}) {
+		t.Fatal(*calls)
+	}
+
+	useHooks(t, "AfterFind")
+	if _, err := newHstest().Selects(); !errors.Is(err, errHook) {
+		t.Fatal(err)
+	}
+}
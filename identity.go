@@ -0,0 +1,126 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql"
+	. "github.com/donnie4w/gdao/base"
+	"slices"
+	"strings"
+)
+
+// UseIdentity sets the auto-increment or identity column of the table, whose value is generated by the database.
+// When the entity does not set the column, Insert and ExecBatch read the generated key back and write it
+// into the entity: with returning for PostgreSQL, SQLite, MariaDB and Firebird, output inserted. for SQL Server,
+// returning into for Oracle, and sql.Result.LastInsertId for MySQL and the other databases.
+// The key is also returned by the LastInsertId of the result. The entity classes built with gdaoBuilder
+// call UseIdentity with the identity column of the table.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.SetName("donnie")
+//	hs.Insert()
+//	fmt.Println(hs.GetId()) // insert into hstest(name)values(?) returning id
+func (t *Table[T]) UseIdentity(column Column[T]) {
+	t.identity = unqualify(column.Name())
+}
+
+// keyResult is the result of an insert whose generated key was read with a returning clause
+type keyResult struct {
+	sql.Result
+	key any
+}
+
+func (r *keyResult) LastInsertId() (int64, error) {
+	return AsInt64(r.key), nil
+}
+
+func (r *keyResult) RowsAffected() (int64, error) {
+	if r.Result != nil {
+		return r.Result.RowsAffected()
+	}
+	return 1, nil
+}
+
+// generatedKey reports whether the insert of the columns generates the key of the identity column
+func (t *Table[T]) generatedKey(columns []string) bool {
+	return t.identity != "" && !slices.Contains(columns, t.identity)
+}
+
+// insert executes the insert statement of the columns, and writes the key of the identity column
+// generated by the database into the entity
+func (t *Table[T]) insert(g DBhandle, sqlstr string, columns []string, args []any) (rs sql.Result, err error) {
+	if !t.generatedKey(columns) {
+		return g.ExecuteUpdateContext(t.getContext(), sqlstr, args...)
+	}
+	var key any
+	output, returning := GetDialect(g.GetDBType()).Returning([]string{t.identity})
+	switch {
+	case output == "" && returning == "":
+		if rs, err = g.ExecuteUpdateContext(t.getContext(), sqlstr, args...); err == nil {
+			if id, e := rs.LastInsertId(); e == nil {
+				key = id
+			}
+		}
+	case strings.Contains(returning, "?"):
+		var id int64
+		args = append(append([]any{}, args...), sql.Out{Dest: &id})
+		if rs, err = g.ExecuteUpdateContext(t.getContext(), returningSql(sqlstr, output, returning), args...); err == nil {
+			key = id
+			rs = &keyResult{rs, key}
+		}
+	default:
+		bean := g.ExecuteQueryBeanContext(t.getContext(), returningSql(sqlstr, output, returning), args...)
+		if err = bean.GetError(); err == nil {
+			if f := bean.FirstField(); f != nil {
+				key = f.Value()
+			}
+			rs = &keyResult{nil, key}
+		}
+	}
+	if key != nil {
+		t.setEntity(t.identity, key)
+	}
+	return
+}
+
+// insertBatch executes the insert batch of the columns, and writes the key of the identity column
+// generated for the last row into the entity. The rows are inserted one statement at a time
+// when their keys are read with a returning clause, the key of every row is returned by the
+// LastInsertId of its result.
+func (t *Table[T]) insertBatch(g DBhandle, sqlstr string, columns []string, args [][]any) ([]sql.Result, error) {
+	if output, returning := GetDialect(g.GetDBType()).Returning([]string{t.identity}); output == "" && returning == "" {
+		rs, err := t.executeBatch(g, sqlstr, args)
+		if err == nil && len(rs) > 0 && len(rs) == len(args) {
+			if id, e := rs[len(rs)-1].LastInsertId(); e == nil {
+				t.setEntity(t.identity, id)
+			}
+		}
+		return rs, err
+	}
+	rs := make([]sql.Result, 0, len(args))
+	for _, row := range args {
+		r, err := t.insert(g, sqlstr, columns, row)
+		if err != nil {
+			return rs, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// returningSql adds the output clause before VALUES and the returning clause to the insert statement
+func returningSql(sqlstr, output, returning string) string {
+	if output != "" {
+		if head, row, tail, ok := splitInsert(sqlstr); ok {
+			sqlstr = head + output + " values" + row + tail
+		}
+	}
+	return sqlstr + returning
+}
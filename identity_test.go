@@ -0,0 +1,106 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	. "github.com/donnie4w/gdao/base"
+	"testing"
+)
+
+func Test_returningSql(t *testing.T) {
+	const insert = "insert  into hstest(name,age )values(?,?)"
+	tests := []struct {
+		dbtype DBType
+		want   string
+	}{
+		{MYSQL, insert},
+		{POSTGRESQL, insert + " returning id"},
+		{SQLITE, insert + " returning id"},
+		{MARIADB, insert + " returning id"},
+		{FIREBIRD, insert + " returning id"},
+		{ORACLE, insert + " returning id into ?"},
+		{SQLSERVER, "insert  into hstest(name,age ) output inserted.id values(?,?)"},
+	}
+	for _, tt := range tests {
+		output, returning := GetDialect(tt.dbtype).Returning([]string{"id"})
+		if got := returningSql(insert, output, returning); got != tt.want {
+			t.Fatalf("%v:\n got: %s\nwant: %s", tt.dbtype, got, tt.want)
+		}
+	}
+}
+
+func Test_InsertIdentity(t *testing.T) {
+	tests := []struct {
+		dbtype DBType
+		want   string
+	}{
+		{MYSQL, "insert  into hstest(name )values(?)[a]"},
+		{POSTGRESQL, "insert  into hstest(name )values($1) returning id[a]"},
+		{SQLSERVER, "insert  into hstest(name ) output inserted.id values(@p1)[a]"},
+	}
+	for _, tt := range tests {
+		d := useTestDB(t, tt.dbtype, []string{"id"}, []driver.Value{int64(8)})
+		d.insertId = 8
+		hs := newHstest()
+		hs.UseIdentity(hs.ID)
+		hs.SetName("a")
+		rs, err := hs.Insert()
+		if err != nil {
+			t.Fatal(tt.dbtype, err)
+		}
+		checkStatements(t, d, tt.want)
+		if id, _ := rs.LastInsertId(); id != 8 || hs.GetId() != 8 {
+			t.Fatal(tt.dbtype, id, hs.GetId())
+		}
+	}
+
+	d := useTestDB(t, POSTGRESQL, nil)
+	hs := newHstest()
+	hs.UseIdentity(hs.ID)
+	hs.SetId(3)
+	if _, err := hs.Insert(); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, "insert  into hstest(id )values($1)[3]")
+}
+
+func Test_insertBatch(t *testing.T) {
+	d := useTestDB(t, POSTGRESQL, []string{"id"}, []driver.Value{int64(9)})
+	hs := newHstest()
+	hs.UseIdentity(hs.ID)
+	hs.SetName("a")
+	hs.AddBatch()
+	hs.SetName("b")
+	hs.AddBatch()
+	rs, err := hs.ExecBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, " insert  into hstest(name )values($1) returning id[a]", " insert  into hstest(name )values($1) returning id[b]")
+	if len(rs) != 2 {
+		t.Fatal(rs)
+	}
+	if id, _ := rs[0].LastInsertId(); id != 9 || hs.GetId() != 9 {
+		t.Fatal(id, hs.GetId())
+	}
+
+	d = useTestDB(t, MYSQL, nil)
+	d.insertId = 5
+	hs = newHstest()
+	hs.UseIdentity(hs.ID)
+	hs.SetName("a")
+	hs.AddBatch()
+	if _, err = hs.ExecBatch(); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, " insert  into hstest(name )values(?)[a]")
+	if hs.GetId() != 5 {
+		t.Fatal(hs.GetId())
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"context"
+	"database/sql"
+	. "github.com/donnie4w/gdao/base"
+	"iter"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StatementKind is the kind of execution of a Statement
+type StatementKind int8
+
+const (
+	// StmtQueryBeans selects the rows of the statement, see DBhandle.ExecuteQueryBeans
+	StmtQueryBeans StatementKind = iota + 1
+	// StmtQueryBean selects the first row of the statement, see DBhandle.ExecuteQueryBean
+	StmtQueryBean
+	// StmtQueryIter selects the rows of the statement one at a time, see DBhandle.ExecuteQueryIter
+	StmtQueryIter
+	// StmtUpdate executes an insert, update, delete or other statement, see DBhandle.ExecuteUpdate
+	StmtUpdate
+	// StmtBatch executes the statement once per row of arguments, see DBhandle.ExecuteBatch
+	StmtBatch
+)
+
+func (k StatementKind) String() string {
+	switch k {
+	case StmtQueryBeans:
+		return "QueryBeans"
+	case StmtQueryBean:
+		return "QueryBean"
+	case StmtQueryIter:
+		return "QueryIter"
+	case StmtUpdate:
+		return "Update"
+	case StmtBatch:
+		return "Batch"
+	}
+	return "Unknown"
+}
+
+// Statement is a statement executed by gdao, passed through the interceptors set by Use.
+// An interceptor can change the statement, or a copy of it, before calling the next Executor.
+type Statement struct {
+	Kind StatementKind
+
+	// Ctx is the context of the execution
+	Ctx context.Context
+
+	// Sql is the statement with ? bind markers, before the rewriting of the placeholders for the database
+	Sql string
+
+	// Args are the arguments of the statement, nil for a batch
+	Args []any
+
+	// BatchArgs are the arguments of every execution of a batch
+	BatchArgs [][]any
+
+	// DBType is the type of the database
+	DBType DBType
+
+	// DataSource is the name of the database set by NameDataSource, "default" for the database of Init
+	DataSource string
+
+	// Tx reports whether the statement is executed in a transaction
+	Tx bool
+
+	gdbc *gdbcHandler
+}
+
+// Result is the result of the execution of a Statement, holding the field of its kind
+type Result struct {
+	// Beans are the rows of StmtQueryBeans
+	Beans []*DataBean
+
+	// Bean is the row of StmtQueryBean
+	Bean *DataBean
+
+	// Iter is the iterator of StmtQueryIter, the rows are read as the iteration advances,
+	// after the interceptors return
+	Iter iter.Seq2[*DataBean, error]
+
+	// Result is the result of StmtUpdate
+	Result sql.Result
+
+	// Results are the results of StmtBatch
+	Results []sql.Result
+
+	// Duration is the time the database took to execute the statement, 0 if it was not executed.
+	// The Duration of StmtQueryIter is set when the iteration ends
+	Duration time.Duration
+}
+
+// Executor executes a statement
+type Executor func(stmt *Statement) (*Result, error)
+
+// Interceptor wraps the Executor of the statements. It returns an Executor that sees every statement,
+// and that can change it before calling next, inspect the result and the error returned by next,
+// or return a result or an error without calling next.
+type Interceptor func(next Executor) Executor
+
+var (
+	interceptorMux sync.Mutex
+	interceptors   []Interceptor
+	executor       atomic.Pointer[Executor]
+)
+
+// Use adds interceptors to the chain wrapping every statement executed by gdao: the queries,
+// updates and batches of Table, of the transactions, of gdaoMapper, of sqlBuilder and of the Execute functions.
+// The interceptor added first is the outermost one.
+//
+// Example:
+//
+//	gdao.Use(func(next gdao.Executor) gdao.Executor {
+//	    return func(stmt *gdao.Statement) (*gdao.Result, error) {
+//	        r, err := next(stmt)
+//	        if r != nil {
+//	            log.Println(stmt.DataSource, stmt.Sql, stmt.Args, r.Duration, err)
+//	        }
+//	        return r, err
+//	    }
+//	})
+func Use(interceptor ...Interceptor) {
+	interceptorMux.Lock()
+	defer interceptorMux.Unlock()
+	interceptors = append(interceptors, interceptor...)
+	buildExecutor()
+}
+
+// ClearInterceptors removes the interceptors added by Use
+func ClearInterceptors() {
+	interceptorMux.Lock()
+	defer interceptorMux.Unlock()
+	interceptors = nil
+	buildExecutor()
+}
+
+func buildExecutor() {
+	if len(interceptors) == 0 {
+		executor.Store(nil)
+		return
+	}
+	var e Executor = execute
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		e = interceptors[i](e)
+	}
+	executor.Store(&e)
+}
+
+// intercept executes the statement through the interceptors, in a span if there is a Tracer
+func intercept(stmt *Statement) (*Result, error) {
+	if Tracing() {
+		return traceStatement(stmt)
+	}
+	return runInterceptors(stmt)
+}
+
+func runInterceptors(stmt *Statement) (*Result, error) {
+	if e := executor.Load(); e != nil {
+		return (*e)(stmt)
+	}
+	return execute(stmt)
+}
+
+// execute is the innermost Executor, executing the statement with the database of the handle
+func execute(stmt *Statement) (r *Result, err error) {
+	g := stmt.gdbc
+	r = &Result{}
+	start := time.Now()
+	switch stmt.Kind {
+	case StmtQueryBeans:
+		sqlstr := parseSql(g.DBType, stmt.Sql, stmt.Args)
+		r.Beans, err = stmtExec.executeQueryBeans(stmt.Ctx, g.TX, g.DB, sqlstr, stmt.Args...)
+	case StmtQueryBean:
+		sqlstr := parseSql(g.DBType, stmt.Sql, stmt.Args)
+		r.Bean, err = stmtExec.executeQueryBean(stmt.Ctx, g.TX, g.DB, sqlstr, stmt.Args...)
+	case StmtQueryIter:
+		sqlstr, ctx, args := parseSql(g.DBType, stmt.Sql, stmt.Args), stmt.Ctx, stmt.Args
+		r.Iter = timedIter(stmt, r, executeQueryIter(func() (*sql.Rows, error) {
+			return stmtExec.executeQueryRows(ctx, g.TX, g.DB, sqlstr, args...)
+		}))
+		return
+	case StmtUpdate:
+		sqlstr := parseSql(g.DBType, stmt.Sql, stmt.Args)
+		r.Result, err = stmtExec.executeUpdate(stmt.Ctx, g.TX, g.DB, sqlstr, stmt.Args...)
+	case StmtBatch:
+		sqlstr := parseSql(g.DBType, stmt.Sql, stmt.BatchArgs)
+		r.Results, err = executeBatch(stmt.Ctx, g.TX, g.DB, sqlstr, stmt.BatchArgs)
+	}
+	r.Duration = time.Since(start)
+	recordSlowQuery(stmt, r.Duration)
+	recordMetrics(stmt, r.Duration, err)
+	return
+}
+
+// timedIter returns the iterator recording the statement when the iteration ends: the rows are read
+// after the interceptors return, so the Duration of the result is only known then
+func timedIter(stmt *Statement, r *Result, seq iter.Seq2[*DataBean, error]) iter.Seq2[*DataBean, error] {
+	return func(yield func(*DataBean, error) bool) {
+		start := time.Now()
+		var err error
+		defer func() {
+			r.Duration = time.Since(start)
+			recordSlowQuery(stmt, r.Duration)
+			recordMetrics(stmt, r.Duration, err)
+		}()
+		for bean, e := range seq {
+			if e != nil {
+				err = e
+			}
+			if !yield(bean, e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	"errors"
+	. "github.com/donnie4w/gdao/base"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_queryIterRecorded(t *testing.T) {
+	d := useTestDB(t, MYSQL, []string{"id"}, []driver.Value{int64(1)}, []driver.Value{int64(2)})
+	var results []*Result
+	Use(func(next Executor) Executor {
+		return func(stmt *Statement) (*Result, error) {
+			r, err := next(stmt)
+			if stmt.Kind == StmtQueryIter {
+				results = append(results, r)
+			}
+			return r, err
+		}
+	})
+	SlowQueryThreshold(time.Nanosecond)
+	EnableMetrics(true)
+	t.Cleanup(func() {
+		ClearInterceptors()
+		SlowQueryThreshold(0)
+		ResetSlowQueries()
+		EnableMetrics(false)
+		ResetMetrics()
+	})
+	counter := func(name string) (n int64) {
+		counters, _, _ := MetricsSnapshot()
+		for k, v := range counters {
+			if strings.HasPrefix(k, name+"{") {
+				n += v
+			}
+		}
+		return
+	}
+
+	hs := newHstest()
+	seq := hs.SelectsIter()
+	if len(results) != 1 || results[0].Duration != 0 || len(SlowQueries()) != 0 || counter(MetricQueries) != 0 {
+		t.Fatal("the statement is recorded before the iteration", results, SlowQueries())
+	}
+	n := 0
+	for _, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != 2 || results[0].Duration == 0 {
+		t.Fatal(n, results[0].Duration)
+	}
+	if s := SlowQueries(); len(s) != 1 || s[0].Count != 1 || counter(MetricQueries) != 1 || counter(MetricErrors) != 0 {
+		t.Fatal(s, counter(MetricQueries), counter(MetricErrors))
+	}
+
+	d.queryErr = errors.New("query failed")
+	for _, err := range hs.SelectsIter() {
+		if err == nil || err.Error() != "query failed" {
+			t.Fatal(err)
+		}
+	}
+	if counter(MetricQueries) != 2 || counter(MetricErrors) != 1 {
+		t.Fatal(counter(MetricQueries), counter(MetricErrors))
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"fmt"
+	. "github.com/donnie4w/gdao/base"
+	"github.com/donnie4w/gdao/gdaoCache"
+	"reflect"
+	"strings"
+)
+
+// join is one JOIN clause of a query built by the Table DSL
+type join struct {
+	sql     string
+	args    []any
+	columns []string
+	typ     reflect.Type
+}
+
+// joinTable is implemented by every standardized entity class through the embedded Table
+type joinTable interface {
+	TableBase
+	joinSpec() (from, onSql string, args []any, columns []string)
+}
+
+// Alias returns a copy of the entity with a table alias, whose fields are qualified with the alias,
+// so that the conditions and columns built from the copy stay unambiguous in a join.
+// An empty alias qualifies the fields with the table name. The entity is left unchanged.
+//
+// Example:
+//
+//	hs := gdao.Alias(dao.NewHstest(), "h")
+//	order := gdao.Alias(dao.NewOrders(), "o")
+//	hs.InnerJoin(order, hs.Id.EqField(order.UserId))
+//	// select h.id,h.name,... from hstest h inner join orders o on h.id=o.user_id
+func Alias[T any](entity *T, alias string) *T {
+	e, ok := any(entity).(interface{ table() *Table[T] })
+	if !ok {
+		return entity
+	}
+	r, t := entityOf(entity, e.table().clone())
+	qualifier := alias
+	if qualifier == "" {
+		qualifier = t.TableName()
+	}
+	fields := make(map[any]any)
+	val := reflect.ValueOf(r).Elem()
+	for i := 0; i < val.NumField(); i++ {
+		f := val.Field(i)
+		if f.Kind() != reflect.Ptr || f.IsNil() || !f.CanSet() || f.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		fb, ok := f.Interface().(FieldBase)
+		if !ok {
+			continue
+		}
+		name := f.Elem().FieldByName("FieldName")
+		if !name.IsValid() || name.Kind() != reflect.String {
+			continue
+		}
+		nf := reflect.New(f.Type().Elem())
+		nf.Elem().FieldByName("FieldName").SetString(qualifier + "." + unqualify(fb.Name()))
+		fields[f.Interface()] = nf.Interface()
+		f.Set(nf)
+	}
+	t.setAlias(alias, qualifier, fields)
+	return r
+}
+
+func (t *Table[T]) setAlias(alias string, qualifier string, fields map[any]any) {
+	t.alias, t.qualifier = alias, qualifier
+	for i, c := range t.columns {
+		if nc, ok := fields[c]; ok {
+			t.columns[i] = nc.(Column[T])
+		}
+	}
+}
+
+func (t *Table[T]) joinSpec() (from, onSql string, args []any, columns []string) {
+	from = t.tableName
+	if t.alias != "" {
+		from = t.tableName + " " + t.alias
+	}
+	where, args := t.scopedWhere(func(name string) string { return qualify(t.qualifierName(), name) })
+	onSql = strings.TrimPrefix(where, " where ")
+	// the on conditions are joined with and, the conditions of the table joined with or are grouped
+	if where == t.whereSql && t.whereOp == "or" {
+		onSql = "(" + onSql + ")"
+	}
+	columns = make([]string, len(t.columns))
+	for i, c := range t.columns {
+		columns[i] = qualify(t.qualifierName(), c.Name())
+	}
+	return from, onSql, args, columns
+}
+
+// InnerJoin adds an INNER JOIN of the table of another standardized entity class to the query.
+// The on conditions are built from the fields of T, typically with EqField; conditions set
+// by Where on the joined entity are appended to the ON clause of the join.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	order := dao.NewOrders()
+//	order.Where(order.Status.EQ(1))
+//	hs.InnerJoin(order, hs.Id.EqField(order.UserId)).Where(hs.Age.GT(18))
+//	// select hstest.id,... from hstest inner join orders on id=user_id and status=? where age>?
+//	hslist, err := hs.Selects()
+func (t *Table[T]) InnerJoin(table TableBase, on ...*Where[T]) *Table[T] {
+	return t.join(" inner join ", table, on)
+}
+
+// LeftJoin adds a LEFT JOIN of the table of another standardized entity class to the query.
+// See InnerJoin for the on conditions.
+func (t *Table[T]) LeftJoin(table TableBase, on ...*Where[T]) *Table[T] {
+	return t.join(" left join ", table, on)
+}
+
+// RightJoin adds a RIGHT JOIN of the table of another standardized entity class to the query.
+// See InnerJoin for the on conditions.
+func (t *Table[T]) RightJoin(table TableBase, on ...*Where[T]) *Table[T] {
+	return t.join(" right join ", table, on)
+}
+
+func (t *Table[T]) join(kind string, table TableBase, on []*Where[T]) *Table[T] {
+	jt, ok := table.(joinTable)
+	if !ok {
+		if Logger.IsVaild {
+			Logger.Warn("[JOIN] unsupported table ", table.TableName())
+		}
+		return t
+	}
+	from, whereSql, whereArgs, columns := jt.joinSpec()
+	j := &join{columns: columns, typ: reflect.TypeOf(table)}
+	conditions := append(make([]*Where[T], 0, len(on)+1), on...)
+	if whereSql != "" {
+		conditions = append(conditions, &Where[T]{WhereSql: whereSql, Values: whereArgs})
+	}
+	j.sql = kind + from
+	if len(conditions) > 0 {
+		w := WhereJoin("and", conditions...)
+		j.sql = j.sql + " on " + w.WhereSql
+		j.args = w.Values
+	}
+	t.joins = append(t.joins, j)
+	return t
+}
+
+// fromSql returns the table of the query with its alias and joins
+func (t *Table[T]) fromSql() string {
+	return t.fromSqlHint("")
+}
+
+// fromSqlHint returns the from clause with the table hint, such as a lock, written after the table name
+func (t *Table[T]) fromSqlHint(hint string) string {
+	from := t.tableName
+	if t.alias != "" {
+		from = from + " " + t.alias
+	}
+	from = from + hint
+	for _, j := range t.joins {
+		from = from + j.sql
+	}
+	return from
+}
+
+// queryArgs returns a new slice of the arguments of the query without the limit,
+// the arguments of the joins come first
+func (t *Table[T]) queryArgs() []any {
+	_, whereArgs := t.whereClause()
+	args := make([]any, 0, len(whereArgs)+len(t.havingArgs)+2)
+	for _, j := range t.joins {
+		args = append(args, j.args...)
+	}
+	args = append(args, whereArgs...)
+	return append(args, t.havingArgs...)
+}
+
+// columnName returns the name of the column in the select list,
+// qualified with the table name or alias when the query has joins
+func (t *Table[T]) columnName(name string) string {
+	if len(t.joins) == 0 {
+		return name
+	}
+	return qualify(t.qualifierName(), name)
+}
+
+func qualify(qualifier, name string) string {
+	if strings.Contains(name, ".") || strings.Contains(name, "(") {
+		return name
+	}
+	return qualifier + "." + name
+}
+
+func unqualify(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// JoinRow is a row of a join query scanned into the entity of the table and the joined entities
+type JoinRow[T any] struct {
+	Row *T
+
+	// Joined holds one entity per join, in the order the joins were added
+	Joined []any
+}
+
+// JoinedOf returns the first joined entity of type U in the row, or nil if there is none.
+//
+// Example:
+//
+//	rows, _ := hs.SelectsJoined()
+//	for _, row := range rows {
+//	    order := gdao.JoinedOf[dao.Orders](row)
+//	    fmt.Println(row.Row, order)
+//	}
+func JoinedOf[U any, T any](row *JoinRow[T]) *U {
+	for _, v := range row.Joined {
+		if u, ok := v.(*U); ok {
+			return u
+		}
+	}
+	return nil
+}
+
+// SelectsJoined executes the join query with all the columns of the table and of the joined tables,
+// and scans every row into a new entity of each of them.
+func (t *Table[T]) SelectsJoined() (_r []*JoinRow[T], err error) {
+	columns := make([]string, 0, len(t.columns))
+	for _, c := range t.columns {
+		columns = append(columns, t.columnName(c.Name()))
+	}
+	for _, j := range t.joins {
+		columns = append(columns, j.columns...)
+	}
+	g := t.getDB(true)
+	if g == nil {
+		return nil, errInit
+	}
+	sqlstr, args, err := t.selectSql(g, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if Logger.IsVaild {
+		Logger.Debug("[SELETE JOIN]["+sqlstr+"]", args)
+	}
+	databeans := g.ExecuteQueryBeansContext(t.getContext(), sqlstr, args...)
+	if err = databeans.GetError(); err != nil {
+		return
+	}
+	_r = make([]*JoinRow[T], 0, databeans.Len())
+	for _, bean := range databeans.Beans {
+		row := &JoinRow[T]{Row: new(T), Joined: make([]any, len(t.joins))}
+		index := 1
+		if err = scanIndex(row.Row, bean, columns[:len(t.columns)], &index); err != nil {
+			return nil, err
+		}
+		for i, j := range t.joins {
+			entity := reflect.New(j.typ.Elem()).Interface()
+			if err = scanIndex(entity, bean, j.columns, &index); err != nil {
+				return nil, err
+			}
+			row.Joined[i] = entity
+		}
+		_r = append(_r, row)
+	}
+	return
+}
+
+// qualifierName returns the alias of the table, or its name if it has no alias
+func (t *Table[T]) qualifierName() string {
+	if t.qualifier != "" {
+		return t.qualifier
+	}
+	return t.tableName
+}
+
+// scanIndex scans the fields of bean starting at index into the entity, one field per column
+func scanIndex(entity any, bean *DataBean, columns []string, index *int) error {
+	scanner, ok := entity.(Scanner)
+	if !ok {
+		return fmt.Errorf("%s is not a standardized entity class", reflect.TypeOf(entity))
+	}
+	scanner.ToGdao()
+	for _, c := range columns {
+		scanner.Scan(unqualify(c), bean.ValueByIndex(*index))
+		*index++
+	}
+	if loader, ok := entity.(Loader); ok {
+		loader.Loaded()
+	}
+	if h, ok := entity.(AfterFinder); ok {
+		return h.AfterFind()
+	}
+	return nil
+}
+
+// projection returns the names of the selected columns of SelectInto, all the columns of the table
+// and of the joined tables if columns is empty
+func (t *Table[T]) projection(columns []Column[T]) []string {
+	if len(columns) > 0 {
+		return t.columnNames(columns)
+	}
+	names := t.columnNames(t.columns)
+	for _, j := range t.joins {
+		names = append(names, j.columns...)
+	}
+	return names
+}
+
+// SelectInto executes the query of the table selecting the columns and scans the rows into the struct R
+// with DataBean.Scan, matching the column names, or their aliases, to the field names of R case-insensitively,
+// or to its Set methods. The columns can be the fields of the table, of the joined tables, and the functions
+// of the fields such as Count and Sum, named by AS. All the columns of the table and of the joined tables
+// are selected if columns is empty. The rows are cached like the rows of Selects.
+//
+// Example:
+//
+//	type AgeCount struct {
+//	    Age   int64
+//	    Total int64
+//	}
+//	hs := dao.NewHstest()
+//	hs.Where(hs.Age.GT(18)).GroupBy(hs.Age).OrderBy(hs.Age.Asc())
+//	list, err := gdao.SelectInto[AgeCount](&hs.Table, hs.Age, hs.Id.Count().AS(base.Col("total")))
+func SelectInto[R any, T any](t *Table[T], columns ...Column[T]) (_r []*R, err error) {
+	g := t.getDB(true)
+	if g == nil {
+		return nil, errInit
+	}
+	sqlstr, args, err := t.selectSql(g, t.projection(columns))
+	if err != nil {
+		return nil, err
+	}
+
+	if Logger.IsVaild {
+		Logger.Debug("[SELETE INTO]["+sqlstr+"]", args)
+	}
+	classname := t.getClassname()
+	domain, iscache := t.useCache()
+	var condition *gdaoCache.Condition
+	if iscache {
+		condition = gdaoCache.NewCondition("[]*"+reflect.TypeFor[R]().String(), sqlstr, args...)
+		if result := gdaoCache.GetCacheContext(t.getContext(), domain, classname, condition); result != nil {
+			if Logger.IsVaild {
+				Logger.Debug("[GET CACHE]["+sqlstr+"]", args)
+			}
+			return result.([]*R), nil
+		}
+	}
+	databeans := g.ExecuteQueryBeansContext(t.getContext(), sqlstr, args...)
+	if err = databeans.GetError(); err != nil {
+		return
+	}
+	_r = make([]*R, 0, databeans.Len())
+	for _, bean := range databeans.Beans {
+		r := new(R)
+		if err = bean.ScanAndFree(r); err != nil {
+			return nil, err
+		}
+		_r = append(_r, r)
+	}
+	if iscache {
+		gdaoCache.SetCache(domain, classname, condition, _r)
+		if Logger.IsVaild {
+			Logger.Debug("[SET CACHE]["+sqlstr+"]", args)
+		}
+	}
+	return
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	. "github.com/donnie4w/gdao/base"
+	"slices"
+	"testing"
+)
+
+func Test_joinOn(t *testing.T) {
+	h := Alias(newHstest(), "h")
+	o := Alias(newHstest(), "o")
+	o.Where(Or(o.AGE.LT(10), o.AGE.GT(60)))
+	h.InnerJoin(o, Or(h.ID.EqField(o.ID), h.NAME.EqField(o.NAME))).Where(h.AGE.EQ(18))
+	where, _ := h.whereClause()
+	checkSql(t, h.fromSql()+where, h.queryArgs(),
+		"hstest h inner join hstest o on (h.id=o.id or h.name=o.name) and (o.age<? or o.age>?) where h.age=?", 10, 60, 18)
+
+	h = Alias(newHstest(), "h")
+	o = Alias(newHstest(), "o")
+	h.LeftJoin(o, Or(h.ID.EqField(o.ID), h.NAME.EqField(o.NAME)))
+	checkSql(t, h.fromSql(), h.queryArgs(), "hstest h left join hstest o on h.id=o.id or h.name=o.name")
+
+	h = Alias(newHstest(), "h")
+	o = Alias(newHstest(), "o")
+	o.Where(o.AGE.GT(18), o.NAME.EQ("donnie"))
+	h.InnerJoin(o, h.ID.EqField(o.ID), h.VERSION.EqField(o.VERSION))
+	checkSql(t, h.fromSql(), h.queryArgs(),
+		"hstest h inner join hstest o on h.id=o.id and h.version=o.version and o.age>? and o.name=?", 18, "donnie")
+}
+
+func Test_SelectsJoined(t *testing.T) {
+	d := useTestDB(t, MYSQL, []string{"id", "name", "age", "version", "id", "name", "age", "version"},
+		[]driver.Value{int64(1), "a", int64(18), int64(0), int64(2), "b", int64(20), int64(0)})
+	h := Alias(newHstest(), "h")
+	o := Alias(newHstest(), "o")
+	h.LeftJoin(o, h.ID.EqField(o.AGE)).Where(h.NAME.EQ("a"))
+	rows, err := h.SelectsJoined()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := d.statements()
+	if len(s) != 1 || s[0].String() != " select h.id,h.name,h.age,h.version,o.id,o.name,o.age,o.version from hstest h left join hstest o on h.id=o.age where h.name=?[a]" {
+		t.Fatal(s)
+	}
+	if len(rows) != 1 || rows[0].Row.GetId() != 1 || len(rows[0].Joined) != 1 {
+		t.Fatal(rows)
+	}
+	if joined := JoinedOf[hstest](rows[0]); joined == nil || joined.GetId() != 2 || joined.GetName() != "b" {
+		t.Fatal(joined)
+	}
+	if JoinedOf[struct{}](rows[0]) != nil {
+		t.Fatal("no joined entity of the type")
+	}
+}
+
+func Test_columnName(t *testing.T) {
+	hs := newHstest()
+	if s := hs.columnName("id"); s != "id" {
+		t.Fatal(s)
+	}
+	hs.InnerJoin(Alias(newHstest(), "o"), hs.ID.EqField(hs.AGE))
+	for name, want := range map[string]string{"id": "hstest.id", "o.id": "o.id", "count(id)": "count(id)"} {
+		if s := hs.columnName(name); s != want {
+			t.Fatal(s, want)
+		}
+	}
+	if s := unqualify("o.id"); s != "id" {
+		t.Fatal(s)
+	}
+}
+
+func Test_projection(t *testing.T) {
+	hs := newHstest()
+	if names := hs.projection(nil); !slices.Equal(names, []string{"id", "name", "age", "version"}) {
+		t.Fatal(names)
+	}
+	if names := hs.projection([]Column[hstest]{hs.AGE, hs.ID.Count().AS(Col("total"))}); !slices.Equal(names, []string{"age", " count(id)  as total"}) {
+		t.Fatal(names)
+	}
+
+	h := Alias(newHstest(), "h")
+	o := Alias(newHstest(), "o")
+	h.InnerJoin(o, h.ID.EqField(o.ID))
+	if names := h.projection(nil); !slices.Equal(names, []string{"h.id", "h.name", "h.age", "h.version", "o.id", "o.name", "o.age", "o.version"}) {
+		t.Fatal(names)
+	}
+	if names := h.projection([]Column[hstest]{h.NAME, o.AGE}); !slices.Equal(names, []string{"h.name", "o.age"}) {
+		t.Fatal(names)
+	}
+}
+
+func Test_SelectInto(t *testing.T) {
+	type ageCount struct {
+		Age   int64
+		Total int64
+	}
+	d := useTestDB(t, MYSQL, []string{"age", "total"}, []driver.Value{int64(18), int64(2)}, []driver.Value{int64(20), int64(1)})
+	hs := newHstest()
+	hs.Where(hs.AGE.GT(10)).GroupBy(hs.AGE)
+	rows, err := SelectInto[ageCount](&hs.Table, hs.AGE, hs.ID.Count().AS(Col("total")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.statements(); len(s) != 1 || s[0].String() != " select age, count(id)  as total from hstest where age>? group by age[10]" {
+		t.Fatal(s)
+	}
+	if len(rows) != 2 || *rows[0] != (ageCount{18, 2}) || *rows[1] != (ageCount{20, 1}) {
+		t.Fatal(rows)
+	}
+}
+
+func Test_Alias(t *testing.T) {
+	hs := newHstest()
+	hs.SetName("a")
+	h := Alias(hs, "h")
+	if h == hs || h.entity != h || h.alias != "h" || h.ID.Name() != "h.id" || h.columns[0].Name() != "h.id" {
+		t.Fatal(h.alias, h.ID.Name(), h.columns[0].Name())
+	}
+	if hs.entity != hs || hs.alias != "" || hs.ID.Name() != "id" || hs.columns[0].Name() != "id" {
+		t.Fatal(hs.alias, hs.ID.Name(), hs.columns[0].Name())
+	}
+	h.SetName("b")
+	if hs.GetName() != "a" || h.GetName() != "b" {
+		t.Fatal(hs.GetName(), h.GetName())
+	}
+	if o := Alias(hs, ""); o.ID.Name() != "hstest.id" || o.alias != "" {
+		t.Fatal(o.ID.Name())
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql"
+	"fmt"
+	. "github.com/donnie4w/gdao/base"
+)
+
+// FindByKey selects the row holding the values of the key columns, such as the primary key or a unique key,
+// nil if there is none. The conditions already set on the Table are ignored, the soft deleted rows are excluded.
+// The entity classes built with gdaoBuilder call it from FindByPK and from the FindBy methods of their unique keys.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	row, err := hs.FindByKey([]base.Column[dao.Hstest]{hs.Id}, 1)
+func (t *Table[T]) FindByKey(columns []Column[T], values ...any) (*T, error) {
+	c, err := t.keyQuery(columns, values)
+	if err != nil {
+		return nil, err
+	}
+	return c.Select()
+}
+
+// UpdateByKey updates the columns set on the entity, except the key columns, of the row holding
+// the values of the key columns set on the entity or loaded from its row. The values loaded from the row
+// are not written, see base.Loader. The conditions already set on the Table are ignored.
+// The entity classes built with gdaoBuilder call it from UpdateByPK.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.SetId(1)
+//	hs.SetName("donnie")
+//	hs.UpdateByKey(hs.Id) // update hstest set name=? where id=?
+func (t *Table[T]) UpdateByKey(columns ...Column[T]) (sql.Result, error) {
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		v, ok := t.value(unqualify(column.Name()))
+		if !ok {
+			return nil, fmt.Errorf("the key column %s of the table %s is not set", column.Name(), t.tableName)
+		}
+		values[i] = v
+	}
+	c, err := t.keyQuery(columns, values)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range columns {
+		delete(c.modifymap, unqualify(column.Name()))
+	}
+	if len(c.modifymap) == 0 {
+		return nil, fmt.Errorf("no column of the table %s is set besides its key", t.tableName)
+	}
+	return c.Update()
+}
+
+// DeleteByKey deletes the row holding the values of the key columns, see Delete.
+// The conditions already set on the Table are ignored.
+// The entity classes built with gdaoBuilder call it from DeleteByPK.
+func (t *Table[T]) DeleteByKey(columns []Column[T], values ...any) (sql.Result, error) {
+	c, err := t.keyQuery(columns, values)
+	if err != nil {
+		return nil, err
+	}
+	return c.Delete()
+}
+
+// keyQuery returns a copy of the Table whose only conditions are the key columns holding the values.
+// The copy acts on the entity of the Table, whose hooks are called and which receives the new version.
+func (t *Table[T]) keyQuery(columns []Column[T], values []any) (*Table[T], error) {
+	if len(columns) == 0 || len(columns) != len(values) {
+		return nil, fmt.Errorf("the key of the table %s has %d columns, %d values given", t.tableName, len(columns), len(values))
+	}
+	wheres := make([]*Where[T], len(columns))
+	for i, column := range columns {
+		if values[i] == nil {
+			wheres[i] = &Where[T]{WhereSql: column.Name() + " is null"}
+		} else {
+			wheres[i] = &Where[T]{WhereSql: column.Name() + "=?", Value: values[i]}
+		}
+	}
+	c := t.Clone().Reset()
+	c.entity = t.entity
+	c.Where(wheres...)
+	return c, nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	"fmt"
+	. "github.com/donnie4w/gdao/base"
+	"testing"
+)
+
+func Test_keyQuery(t *testing.T) {
+	hs := newHstest()
+	hs.Where(hs.AGE.GT(18))
+	c, err := hs.keyQuery([]Column[hstest]{hs.ID, hs.NAME}, []any{1, nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	where, args := c.whereClause()
+	checkSql(t, where, args, " where id=? and name is null", 1)
+	where, args = hs.whereClause()
+	checkSql(t, where, args, " where age>?", 18)
+
+	for _, values := range [][]any{{1}, {1, "a", 2}} {
+		if _, err = hs.keyQuery([]Column[hstest]{hs.ID, hs.NAME}, values); err == nil ||
+			err.Error() != fmt.Sprintf("the key of the table hstest has 2 columns, %d values given", len(values)) {
+			t.Fatal(err)
+		}
+	}
+	if _, err = hs.keyQuery(nil, nil); err == nil {
+		t.Fatal("no key column")
+	}
+}
+
+func Test_UpdateByKey(t *testing.T) {
+	d := useTestDB(t, MYSQL, nil)
+	hs := newHstest()
+	hs.SetId(1)
+	if _, err := hs.UpdateByKey(hs.ID); err == nil {
+		t.Fatal("no column besides the key")
+	}
+	hs.SetName("a")
+	if _, err := hs.UpdateByKey(hs.ID, hs.AGE); err == nil {
+		t.Fatal("key column not set")
+	}
+	if _, err := hs.UpdateByKey(hs.ID); err != nil {
+		t.Fatal(err)
+	}
+	if s := d.statements(); len(s) != 1 || s[0].String() != "update hstest set name=? where id=?[a 1]" {
+		t.Fatal(s)
+	}
+}
+
+func Test_UpdateByKeyLoaded(t *testing.T) {
+	d := useTestDB(t, MYSQL, []string{"id", "name", "age", "version"}, []driver.Value{int64(1), "a", int64(18), int64(3)})
+	hs := newHstest()
+	row, err := hs.Where(hs.ID.EQ(1)).Select()
+	if err != nil || row == nil {
+		t.Fatal(row, err)
+	}
+	d.statements()
+	row.SetName("b")
+	if _, err = row.UpdateByKey(row.ID); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, "update hstest set name=? where id=?[b 1]")
+
+	row.UseVersion(row.VERSION)
+	if _, err = row.UpdateByKey(row.ID); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, "update hstest set name=?,version=version+1 where (id=?) and version=?[b 1 3]")
+
+	if row, err = newHstest().Where(hs.ID.EQ(1)).Select(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = row.UpdateByKey(row.ID); err == nil {
+		t.Fatal("no column is set on the loaded entity")
+	}
+}
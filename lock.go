@@ -0,0 +1,81 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"errors"
+	. "github.com/donnie4w/gdao/base"
+)
+
+// ErrLockWithoutTransaction is returned by the select statements locking their rows, see Table.ForUpdate,
+// when the Table is not used with a transaction: the locks would be released as soon as the statement ends.
+var ErrLockWithoutTransaction = errors.New("row locking requires a transaction, see Table.UseTransaction")
+
+const (
+	lockUpdate int8 = iota + 1
+	lockShare
+)
+
+const (
+	lockNoWait int8 = iota + 1
+	lockSkipLocked
+)
+
+// ForUpdate locks the rows selected by Selects, Select, SelectsIter, SelectsEach and Page until the end
+// of the transaction of the Table, set by UseTransaction. The lock is written by the Dialect of the database,
+// such as for update, or with (updlock, rowlock) for SQL Server. It is kept until Reset is called,
+// and the locking selects do not use the cache.
+//
+// Example:
+//
+//	tx, _ := gdao.NewTransaction()
+//	hs := dao.NewHstest()
+//	hs.UseTransaction(tx)
+//	row, err := hs.Where(hs.Id.EQ(1)).ForUpdate().Select()
+//	// select id,age,... from hstest where id=? for update
+func (t *Table[T]) ForUpdate() *Table[T] {
+	t.lockMode = lockUpdate
+	return t
+}
+
+// ForShare locks the selected rows in share mode until the end of the transaction, see ForUpdate
+func (t *Table[T]) ForShare() *Table[T] {
+	t.lockMode = lockShare
+	return t
+}
+
+// NoWait fails instead of waiting for the rows locked by other transactions,
+// the rows are locked for update unless ForShare is called, see ForUpdate
+func (t *Table[T]) NoWait() *Table[T] {
+	if t.lockMode == 0 {
+		t.lockMode = lockUpdate
+	}
+	t.lockWait = lockNoWait
+	return t
+}
+
+// SkipLocked skips the rows locked by other transactions instead of waiting for them,
+// the rows are locked for update unless ForShare is called, see ForUpdate
+func (t *Table[T]) SkipLocked() *Table[T] {
+	if t.lockMode == 0 {
+		t.lockMode = lockUpdate
+	}
+	t.lockWait = lockSkipLocked
+	return t
+}
+
+// lockClause returns the row locking of the query written by the Dialect of the database
+func (t *Table[T]) lockClause(g DBhandle) (LockClause, error) {
+	if t.lockMode == 0 {
+		return LockClause{}, nil
+	}
+	if _, ok := g.(Transaction); !ok {
+		return LockClause{}, ErrLockWithoutTransaction
+	}
+	return GetDialect(g.GetDBType()).Lock(t.lockMode == lockShare, t.lockWait == lockNoWait, t.lockWait == lockSkipLocked)
+}
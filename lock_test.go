@@ -0,0 +1,111 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"errors"
+	. "github.com/donnie4w/gdao/base"
+	"testing"
+)
+
+func Test_lock(t *testing.T) {
+	forUpdate := func(h *hstest) { h.ForUpdate() }
+	forShare := func(h *hstest) { h.ForShare() }
+	noWait := func(h *hstest) { h.NoWait() }
+	skipLocked := func(h *hstest) { h.SkipLocked() }
+	shareSkipLocked := func(h *hstest) { h.ForShare().SkipLocked() }
+	limit := func(h *hstest) { h.Limit(2); h.ForUpdate() }
+	tests := []struct {
+		dbtype DBType
+		lock   func(*hstest)
+		sql    string
+		err    string
+	}{
+		{MYSQL, forUpdate, " select id,name,age,version from hstest where id>? for update[1]", ""},
+		{MYSQL, shareSkipLocked, " select id,name,age,version from hstest where id>? for share skip locked[1]", ""},
+		{MARIADB, forShare, " select id,name,age,version from hstest where id>? lock in share mode[1]", ""},
+		{MARIADB, shareSkipLocked, "", "lock in share mode nowait is not supported by the mariadb dialect"},
+		{POSTGRESQL, noWait, " select id,name,age,version from hstest where id>$1 for update nowait[1]", ""},
+		{POSTGRESQL, limit, " select id,name,age,version from hstest where id>$1 LIMIT $2 OFFSET 0  for update[1 2]", ""},
+		{ORACLE, skipLocked, " select id,name,age,version from hstest where id>:v1 for update skip locked[1]", ""},
+		{ORACLE, limit, " select id,name,age,version from hstest where rowid in (select rowid from hstest where id>:v1 FETCH FIRST :v2 ROWS ONLY ) for update[1 2]", ""},
+		{ORACLE, forShare, "", "for share is not supported by the oracle dialect"},
+		{SQLSERVER, forUpdate, " select id,name,age,version from hstest with (updlock, rowlock) where id>@p1[1]", ""},
+		{SQLSERVER, forShare, " select id,name,age,version from hstest with (holdlock, rowlock) where id>@p1[1]", ""},
+		{SQLSERVER, noWait, " select id,name,age,version from hstest with (updlock, rowlock, nowait) where id>@p1[1]", ""},
+		{SQLSERVER, shareSkipLocked, " select id,name,age,version from hstest with (holdlock, rowlock, readpast) where id>@p1[1]", ""},
+		{SQLSERVER, limit, " select top 2 id,name,age,version from hstest with (updlock, rowlock) where id>@p1[1]", ""},
+		{DB2, skipLocked, " select id,name,age,version from hstest where id>? for update skip locked data[1]", ""},
+		{DB2, noWait, "", "nowait is not supported by the db2 dialect"},
+		{FIREBIRD, forUpdate, " select id,name,age,version from hstest where id>? with lock[1]", ""},
+		{SQLITE, forUpdate, "", "row locking is not supported by the sqlite dialect"},
+	}
+	for _, tt := range tests {
+		d := useTestDB(t, tt.dbtype, nil)
+		tx, err := NewTransaction()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.statements()
+		hs := newHstest()
+		hs.UseTransaction(tx)
+		hs.Where(hs.ID.GT(1))
+		tt.lock(hs)
+		_, err = hs.Selects()
+		s := d.statements()
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err || len(s) != 0 {
+				t.Fatal(tt.dbtype, err, s)
+			}
+		} else if err != nil || len(s) != 1 || s[0].String() != tt.sql {
+			t.Fatalf("%v:\n got: %v %v\nwant: %s", tt.dbtype, s, err, tt.sql)
+		}
+	}
+}
+
+func Test_lockJoin(t *testing.T) {
+	d := useTestDB(t, ORACLE, nil)
+	tx, err := NewTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.statements()
+	h := Alias(newHstest(), "h")
+	o := Alias(newHstest(), "o")
+	o.Where(o.AGE.GT(5))
+	h.UseTransaction(tx)
+	h.InnerJoin(o, h.ID.EqField(o.ID)).Where(h.NAME.EQ("a")).OrderBy(h.ID.Desc()).Limit(3)
+	h.ForUpdate().SkipLocked()
+	if _, err = h.Selects(); err != nil {
+		t.Fatal(err)
+	}
+	want := " select h.id,h.name,h.age,h.version from hstest h inner join hstest o on h.id=o.id and o.age>:v1" +
+		" where h.rowid in (select h.rowid from hstest h inner join hstest o on h.id=o.id and o.age>:v2 where h.name=:v3 order by h.id desc  FETCH FIRST :v4 ROWS ONLY )" +
+		" order by h.id desc  for update skip locked[5 5 a 3]"
+	if s := d.statements(); len(s) != 1 || s[0].String() != want {
+		t.Fatalf("\n got: %v\nwant: %s", s, want)
+	}
+}
+
+func Test_lockWithoutTransaction(t *testing.T) {
+	d := useTestDB(t, MYSQL, nil)
+	hs := newHstest()
+	if _, err := hs.ForUpdate().Selects(); !errors.Is(err, ErrLockWithoutTransaction) {
+		t.Fatal(err)
+	}
+	if _, err := hs.Select(); !errors.Is(err, ErrLockWithoutTransaction) {
+		t.Fatal(err)
+	}
+	if s := d.statements(); len(s) != 0 {
+		t.Fatal(s)
+	}
+	hs.Reset()
+	if _, err := hs.Selects(); err != nil {
+		t.Fatal(err)
+	}
+}
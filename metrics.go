@@ -0,0 +1,85 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql"
+	"expvar"
+	"fmt"
+	. "github.com/donnie4w/gdao/base"
+	"github.com/donnie4w/gdao/util"
+	"github.com/donnie4w/gofer/hashmap"
+	"time"
+)
+
+// databases are the databases of the DBhandles, for their sql.DBStats
+var databases = hashmap.NewMapL[*sql.DB, struct{}]()
+
+// PublishMetrics turns the metrics on and publishes them with expvar as name, "gdao" if name is empty,
+// served as json by the /debug/vars handler of expvar. See Metrics for their content.
+//
+// Example:
+//
+//	gdao.PublishMetrics("")
+//	http.ListenAndServe(":8080", nil) // curl localhost:8080/debug/vars
+func PublishMetrics(name string) {
+	if name == "" {
+		name = "gdao"
+	}
+	EnableMetrics(true)
+	if expvar.Get(name) == nil {
+		expvar.Publish(name, expvar.Func(func() any { return Metrics() }))
+	}
+}
+
+// SetMetricsSink turns the metrics on and forwards their changes to sink, nil removes the sink.
+// The sql.DBStats are not forwarded, they are returned by DBStats.
+func SetMetricsSink(sink MetricsSink) {
+	EnableMetrics(true)
+	UseMetricsSink(sink)
+}
+
+// Metrics returns the metrics of gdao, named as the Metric constants of the base package:
+//
+//	counters: the statements and the errors per datasource and table, the hits, misses and evictions
+//	          of the prepared statements cache per datasource and of gdaoCache per domain
+//	gauges: the size of the prepared statements cache and the open transactions per datasource
+//	histograms: the time of the statements per datasource and table
+//	databases: the sql.DBStats of the databases of the DBhandles, see DBStats
+func Metrics() map[string]any {
+	counters, gauges, histograms := MetricsSnapshot()
+	return map[string]any{"counters": counters, "gauges": gauges, "histograms": histograms, "databases": DBStats()}
+}
+
+// DBStats returns the sql.DBStats of the databases of the DBhandles, keyed by the names set by NameDataSource,
+// or by the address of the unnamed databases
+func DBStats() map[string]sql.DBStats {
+	m := map[string]sql.DBStats{}
+	databases.Range(func(db *sql.DB, _ struct{}) bool {
+		name := dataSourceName(db)
+		if name == "" {
+			name = fmt.Sprintf("%p", db)
+		}
+		m[name] = db.Stats()
+		return true
+	})
+	return m
+}
+
+// recordMetrics counts the statement executed, and records its time
+func recordMetrics(stmt *Statement, d time.Duration, err error) {
+	if !MetricsEnabled() {
+		return
+	}
+	labels := []string{LabelDataSource, stmt.DataSource, LabelTable, util.StatementTable(stmt.Sql)}
+	AddCounter(MetricQueries, 1, labels...)
+	if err != nil {
+		AddCounter(MetricErrors, 1, labels...)
+	}
+	Observe(MetricQueryDuration, d.Seconds(), labels...)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"errors"
+	. "github.com/donnie4w/gdao/base"
+	"strings"
+)
+
+// ErrOptimisticLock is returned by Table.Update when the table has a version column and no row
+// holds the version set on the entity, because the row was changed or deleted after it was read.
+// The returned error wraps ErrOptimisticLock and should be tested with errors.Is.
+var ErrOptimisticLock = errors.New("optimistic lock failed: the row was changed or deleted")
+
+// UseVersion sets the integer column holding the version of the rows for optimistic locking.
+// Update then increments the column, and when the entity holds a value of the column, only updates
+// the rows still holding that version and returns ErrOptimisticLock if there is none.
+// The new version is written back into the entity after a successful update.
+// The entity classes built with gdaoBuilder.WithVersion call UseVersion when they are created.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.Where(hs.Id.EQ(1))
+//	row, _ := hs.Select()
+//	row.SetName("donnie")
+//	row.Where(row.Id.EQ(1))
+//	if _, err := row.Update(); errors.Is(err, gdao.ErrOptimisticLock) {
+//	    // reload and retry
+//	}
+func (t *Table[T]) UseVersion(column Column[T]) {
+	t.version = unqualify(column.Name())
+}
+
+// versionCondition returns the where clause of an update checking the version v, and its arguments
+func (t *Table[T]) versionCondition(v any) (string, []any) {
+	whereSql, whereArgs := t.whereClause()
+	where := " where " + t.version + "=?"
+	if whereSql != "" {
+		where = " where (" + strings.TrimPrefix(whereSql, " where ") + ") and " + t.version + "=?"
+	}
+	args := make([]any, 0, len(whereArgs)+len(t.havingArgs)+1)
+	args = append(append(args, whereArgs...), v)
+	return where + t.groupSql + t.havingSql, append(args, t.havingArgs...)
+}
+
+// setEntity writes the value of the column into the entity created with the Table, if any
+func (t *Table[T]) setEntity(column string, value any) {
+	if t.entity == nil {
+		return
+	}
+	if scanner, ok := any(t.entity).(Scanner); ok {
+		scanner.Scan(column, value)
+	}
+}
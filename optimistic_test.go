@@ -0,0 +1,58 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_versionCondition(t *testing.T) {
+	hs := newHstest()
+	hs.UseVersion(hs.VERSION)
+	where, args := hs.versionCondition(3)
+	checkSql(t, where, args, " where version=?", 3)
+
+	hs.Where(hs.ID.EQ(1), hs.NAME.EQ("a"))
+	where, args = hs.versionCondition(3)
+	checkSql(t, where, args, " where (id=? and name=?) and version=?", 1, "a", 3)
+
+	hs.GroupBy(hs.NAME)
+	hs.Having(hs.ID.Count().GT(1))
+	where, args = hs.versionCondition(3)
+	checkSql(t, where, args, " where (id=? and name=?) and version=? group by name having  count(id) >?", 1, "a", 3, 1)
+}
+
+func Test_UpdateVersion(t *testing.T) {
+	d := useTestDB(t, MYSQL, nil)
+	hs := newHstest()
+	hs.UseVersion(hs.VERSION)
+	hs.SetName("a")
+	hs.Where(hs.ID.EQ(1))
+	if _, err := hs.Update(); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, "update hstest set name=?,version=version+1 where id=?[a 1]")
+
+	hs.SetVersion(3)
+	if _, err := hs.Update(); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, "update hstest set name=?,version=version+1 where (id=?) and version=?[a 1 3]")
+	if *hs._VERSION != 4 {
+		t.Fatal("the new version is not written into the entity", *hs._VERSION)
+	}
+
+	d.affected = 0
+	if _, err := hs.Update(); !errors.Is(err, ErrOptimisticLock) {
+		t.Fatal(err)
+	}
+	if *hs._VERSION != 4 {
+		t.Fatal(*hs._VERSION)
+	}
+}
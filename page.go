@@ -0,0 +1,130 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"fmt"
+	. "github.com/donnie4w/gdao/base"
+	"github.com/donnie4w/gdao/util"
+	"strings"
+)
+
+// Page is one page of the rows of a query, with the total number of rows of the query
+type Page[T any] struct {
+	// Rows are the rows of the page
+	Rows []*T
+
+	// Total is the number of rows of the query without pagination
+	Total int64
+
+	// PageNo is the number of the page, starting with 1
+	PageNo int64
+
+	// PageSize is the maximum number of rows of a page
+	PageSize int64
+
+	// PageCount is the number of pages of the query
+	PageCount int64
+
+	// HasNext reports whether there is a page after this one
+	HasNext bool
+}
+
+// NewPage returns the page pageNo of the rows of a query selecting total rows in pages of pageSize rows
+func NewPage[T any](rows []*T, total, pageNo, pageSize int64) *Page[T] {
+	if pageNo < 1 {
+		pageNo = 1
+	}
+	p := &Page[T]{Rows: rows, Total: total, PageNo: pageNo, PageSize: pageSize}
+	if pageSize > 0 {
+		p.PageCount = (total + pageSize - 1) / pageSize
+	}
+	p.HasNext = pageNo < p.PageCount
+	return p
+}
+
+// pageOffset returns the number of rows before the page pageNo of pageSize rows
+func pageOffset(pageNo, pageSize int64) (int64, error) {
+	if pageSize < 1 {
+		return 0, fmt.Errorf("invalid page size %d", pageSize)
+	}
+	if pageNo < 1 {
+		pageNo = 1
+	}
+	return (pageNo - 1) * pageSize, nil
+}
+
+// CountSql returns the statement counting the rows of the select statement sqlstr.
+// The order by clause of sqlstr and everything following it, such as a limit, is removed.
+func CountSql(sqlstr string) string {
+	return "select count(*) from (" + util.TrimOrderBy(sqlstr) + ") gdao_count"
+}
+
+// PageSql returns the select statement sqlstr limited to the page pageNo of pageSize rows,
+// written by the Dialect of dbtype, and the arguments of the limit, to be appended to the arguments of sqlstr.
+func PageSql(dbtype DBType, sqlstr string, pageNo, pageSize int64) (string, []any, error) {
+	offset, err := pageOffset(pageNo, pageSize)
+	if err != nil {
+		return "", nil, err
+	}
+	clause, err := GetDialect(dbtype).LimitOffset(offset, pageSize)
+	if err != nil {
+		return "", nil, err
+	}
+	if clause.Top != "" {
+		s := strings.TrimLeft(sqlstr, " \t\r\n")
+		if len(s) < 6 || !strings.EqualFold(s[:6], "select") {
+			return "", nil, fmt.Errorf("cannot paginate the statement [%s]", sqlstr)
+		}
+		sqlstr = s[:6] + " " + clause.Top + s[6:]
+	}
+	return sqlstr + clause.Sql, clause.Args, nil
+}
+
+// Page executes the query with the limit of the page pageNo of pageSize rows, and a count
+// query derived from the same joins, where, group by and having clauses to get the total number of rows.
+// The order by and limit of the query are not used by the count. pageNo starts with 1.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.Where(hs.Age.GT(18)).OrderBy(hs.Id.Desc())
+//	page, err := hs.Page(2, 20)
+//	fmt.Println(page.Total, page.PageCount, page.HasNext, len(page.Rows))
+func (t *Table[T]) Page(pageNo, pageSize int64, columns ...Column[T]) (*Page[T], error) {
+	offset, err := pageOffset(pageNo, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	total, err := t.Count()
+	if err != nil {
+		return nil, err
+	}
+	var rows []*T
+	if offset < total {
+		c := t.Clone()
+		c.Limit2(offset, pageSize)
+		if rows, err = c.Selects(columns...); err != nil {
+			return nil, err
+		}
+	}
+	return NewPage(rows, total, pageNo, pageSize), nil
+}
+
+// countSql returns the statement counting the rows of the query and its arguments
+func (t *Table[T]) countSql() (string, []any) {
+	where, _ := t.whereClause()
+	if t.groupSql == "" && t.havingSql == "" {
+		return t.commentline + " select count(*) from " + t.fromSql() + where, t.queryArgs()
+	}
+	columns := strings.TrimPrefix(t.groupSql, " group by ")
+	if columns == "" {
+		columns = "1"
+	}
+	return t.commentline + " select count(*) from (select " + columns + " from " + t.fromSql() + where + t.groupSql + t.havingSql + ") gdao_count", t.queryArgs()
+}
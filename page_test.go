@@ -0,0 +1,124 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	. "github.com/donnie4w/gdao/base"
+	"testing"
+)
+
+func Test_countSql(t *testing.T) {
+	hs := newHstest()
+	hs.Where(hs.AGE.GT(18))
+	hs.OrderBy(hs.ID.Desc())
+	hs.Limit(10)
+	sqlstr, args := hs.countSql()
+	checkSql(t, sqlstr, args, " select count(*) from hstest where age>?", 18)
+
+	hs.GroupBy(hs.NAME)
+	hs.Having(hs.ID.Count().GT(1))
+	sqlstr, args = hs.countSql()
+	checkSql(t, sqlstr, args, " select count(*) from (select name from hstest where age>? group by name having  count(id) >?) gdao_count", 18, 1)
+
+	h := Alias(newHstest(), "h")
+	o := Alias(newHstest(), "o")
+	o.Where(o.AGE.LT(60))
+	h.InnerJoin(o, h.ID.EqField(o.ID)).Where(h.NAME.EQ("a"))
+	sqlstr, args = h.countSql()
+	checkSql(t, sqlstr, args, " select count(*) from hstest h inner join hstest o on h.id=o.id and o.age<? where h.name=?", 60, "a")
+}
+
+func Test_CountSql(t *testing.T) {
+	tests := []struct{ sql, want string }{
+		{"select * from hstest", "select count(*) from (select * from hstest) gdao_count"},
+		{"select * from hstest where age>? order by id desc limit 10", "select count(*) from (select * from hstest where age>?) gdao_count"},
+		{"select * from hstest where id in (select id from t order by id limit 1) ORDER BY name", "select count(*) from (select * from hstest where id in (select id from t order by id limit 1)) gdao_count"},
+		{"select * from hstest where name='order by'", "select count(*) from (select * from hstest where name='order by') gdao_count"},
+	}
+	for _, tt := range tests {
+		if s := CountSql(tt.sql); s != tt.want {
+			t.Fatalf("\n got: %s\nwant: %s", s, tt.want)
+		}
+	}
+}
+
+func Test_PageSql(t *testing.T) {
+	tests := []struct {
+		dbtype DBType
+		sql    string
+		args   []any
+	}{
+		{MYSQL, "select * from hstest LIMIT ?,? ", []any{int64(20), int64(10)}},
+		{POSTGRESQL, "select * from hstest OFFSET ? LIMIT ? ", []any{int64(20), int64(10)}},
+		{SQLSERVER, "select * from hstest OFFSET ? ROWS FETCH NEXT ? ROWS ONLY ", []any{int64(20), int64(10)}},
+		{INFORMIX, "select skip 20 first 10  * from hstest", nil},
+		{FIREBIRD, "select * from hstest ROWS ? TO ? ", []any{int64(21), int64(30)}},
+	}
+	for _, tt := range tests {
+		sqlstr, args, err := PageSql(tt.dbtype, "select * from hstest", 3, 10)
+		if err != nil {
+			t.Fatal(tt.dbtype, err)
+		}
+		checkSql(t, sqlstr, args, tt.sql, tt.args...)
+	}
+	if _, _, err := PageSql(MYSQL, "select * from hstest", 1, 0); err == nil {
+		t.Fatal("invalid page size")
+	}
+	if _, _, err := PageSql(SYBASE, "select * from hstest", 2, 10); err == nil {
+		t.Fatal("offset is not supported by sybase")
+	}
+	if _, _, err := PageSql(INFORMIX, "with t as (select 1) select * from t", 1, 10); err == nil {
+		t.Fatal("cannot paginate a statement not starting with select")
+	}
+}
+
+func Test_NewPage(t *testing.T) {
+	tests := []struct {
+		total, pageNo, pageSize, pageCount int64
+		hasNext                            bool
+	}{
+		{0, 1, 10, 0, false},
+		{25, 1, 10, 3, true},
+		{25, 3, 10, 3, false},
+		{30, 3, 10, 3, false},
+		{25, 0, 10, 3, true},
+	}
+	for _, tt := range tests {
+		p := NewPage[hstest](nil, tt.total, tt.pageNo, tt.pageSize)
+		if p.PageCount != tt.pageCount || p.HasNext != tt.hasNext || p.PageNo < 1 {
+			t.Fatal(tt, p)
+		}
+	}
+}
+
+func Test_Page(t *testing.T) {
+	d := useTestDB(t, MYSQL, []string{"count(*)"}, []driver.Value{int64(25)})
+	hs := newHstest()
+	hs.Where(hs.AGE.GT(18))
+	hs.OrderBy(hs.ID.Desc())
+	p, err := hs.Page(3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Total != 25 || p.PageCount != 3 || p.HasNext || len(p.Rows) != 1 {
+		t.Fatal(p)
+	}
+	s := d.statements()
+	if len(s) != 2 || s[0].String() != " select count(*) from hstest where age>?[18]" ||
+		s[1].String() != " select id,name,age,version from hstest where age>? order by id desc  LIMIT ?,? [18 20 10]" {
+		t.Fatal(s)
+	}
+
+	if p, err = hs.Page(4, 10); err != nil || len(p.Rows) != 0 || p.Total != 25 {
+		t.Fatal(p, err)
+	}
+	if s = d.statements(); len(s) != 1 {
+		t.Fatal("the page after the last one is not selected", s)
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"context"
+	. "github.com/donnie4w/gdao/base"
+	"iter"
+	"maps"
+)
+
+// Clone returns a copy of the Table with its query conditions, joins, limit, settings and the
+// values set on the entity. The copy is the Table of a copy of the entity, so that the hooks and
+// the values written by gdao, such as a new version, apply to the copy. The copy and the Table
+// can then be changed independently.
+func (t *Table[T]) Clone() *Table[T] {
+	c := t.clone()
+	if t.entity != nil {
+		if _, ct := entityOf(t.entity, c); ct != nil {
+			return ct
+		}
+	}
+	return c
+}
+
+// clone returns a copy of the Table that does not share the state its methods modify
+func (t *Table[T]) clone() *Table[T] {
+	c := *t
+	c.whereArgs = append([]any(nil), t.whereArgs...)
+	c.havingArgs = append([]any(nil), t.havingArgs...)
+	// the joins are shared, a join is not modified once it is added
+	c.joins = append([]*join(nil), t.joins...)
+	c.columns = append([]Column[T](nil), t.columns...)
+	if t.modifymap != nil {
+		c.modifymap = maps.Clone(t.modifymap)
+	}
+	c.loaded = maps.Clone(t.loaded)
+	if t.batchrows != nil {
+		c.batchrows = make([]map[string]any, len(t.batchrows))
+		for i, row := range t.batchrows {
+			c.batchrows[i] = maps.Clone(row)
+		}
+	}
+	return &c
+}
+
+// entityOf returns a copy of the entity embedding the Table c, and the Table of the copy,
+// nil if the entity does not embed a Table
+func entityOf[T any](entity *T, c *Table[T]) (*T, *Table[T]) {
+	r := new(T)
+	*r = *entity
+	e, ok := any(r).(interface{ table() *Table[T] })
+	if !ok {
+		return nil, nil
+	}
+	c.entity = r
+	*e.table() = *c
+	return r, e.table()
+}
+
+// Reset clears the where, group by, having and order by clauses, the joins, the limit, the row locking
+// and Unscoped of the query.
+// The values set on the entity and the settings of the Table, such as the context,
+// the transaction and the alias, are kept.
+func (t *Table[T]) Reset() *Table[T] {
+	t.whereSql, t.whereArgs, t.whereOp = "", nil, ""
+	t.groupSql = ""
+	t.havingSql, t.havingArgs = "", nil
+	t.orderSql = ""
+	t.limit, t.offset, t.limitMode = 0, 0, 0
+	t.joins = nil
+	t.unscoped = false
+	t.lockMode, t.lockWait = 0, 0
+	return t
+}
+
+// Query returns an immutable query on the table of the entity, starting with the conditions already set on it.
+// Every method of Query that changes the query returns a new Query and leaves the receiver unchanged,
+// so a Query can be defined once, shared between goroutines and executed any number of times.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	adults := hs.Query().Where(hs.Age.GE(18)).OrderBy(hs.Id.Desc())
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    list, err := adults.WithContext(r.Context()).Limit(10).Selects()
+//	    ...
+//	}
+func (t *Table[T]) Query() *Query[T] {
+	return &Query[T]{t.Clone()}
+}
+
+// Query is an immutable query on the table of a standardized entity class, see Table.Query
+type Query[T any] struct {
+	t *Table[T]
+}
+
+func (q *Query[T]) with(f func(t *Table[T])) *Query[T] {
+	t := q.t.Clone()
+	f(t)
+	return &Query[T]{t}
+}
+
+// Table returns a copy of the Table of the query
+func (q *Query[T]) Table() *Table[T] {
+	return q.t.Clone()
+}
+
+// Clone returns a copy of the query
+func (q *Query[T]) Clone() *Query[T] {
+	return &Query[T]{q.t.Clone()}
+}
+
+// Reset returns the query without conditions, grouping, ordering, joins and limit
+func (q *Query[T]) Reset() *Query[T] {
+	return q.with(func(t *Table[T]) { t.Reset() })
+}
+
+// Where returns the query with the where clause replaced by the conditions
+func (q *Query[T]) Where(wheres ...*Where[T]) *Query[T] {
+	return q.with(func(t *Table[T]) { t.Where(wheres...) })
+}
+
+// OrderBy returns the query with the order by clause replaced by sorts
+func (q *Query[T]) OrderBy(sorts ...*Sort[T]) *Query[T] {
+	return q.with(func(t *Table[T]) { t.OrderBy(sorts...) })
+}
+
+// GroupBy returns the query with the group by clause replaced by columns
+func (q *Query[T]) GroupBy(columns ...Column[T]) *Query[T] {
+	return q.with(func(t *Table[T]) { t.GroupBy(columns...) })
+}
+
+// Having returns the query with the having clause replaced by havings
+func (q *Query[T]) Having(havings ...*Having[T]) *Query[T] {
+	return q.with(func(t *Table[T]) { t.Having(havings...) })
+}
+
+// InnerJoin returns the query with an inner join added, see Table.InnerJoin
+func (q *Query[T]) InnerJoin(table TableBase, on ...*Where[T]) *Query[T] {
+	return q.with(func(t *Table[T]) { t.InnerJoin(table, on...) })
+}
+
+// LeftJoin returns the query with a left join added, see Table.LeftJoin
+func (q *Query[T]) LeftJoin(table TableBase, on ...*Where[T]) *Query[T] {
+	return q.with(func(t *Table[T]) { t.LeftJoin(table, on...) })
+}
+
+// RightJoin returns the query with a right join added, see Table.RightJoin
+func (q *Query[T]) RightJoin(table TableBase, on ...*Where[T]) *Query[T] {
+	return q.with(func(t *Table[T]) { t.RightJoin(table, on...) })
+}
+
+// Limit returns the query selecting at most limit rows
+func (q *Query[T]) Limit(limit int64) *Query[T] {
+	return q.with(func(t *Table[T]) { t.Limit(limit) })
+}
+
+// Limit2 returns the query selecting at most limit rows after skipping offset rows
+func (q *Query[T]) Limit2(offset, limit int64) *Query[T] {
+	return q.with(func(t *Table[T]) { t.Limit2(offset, limit) })
+}
+
+// WithContext returns the query executed with ctx
+func (q *Query[T]) WithContext(ctx context.Context) *Query[T] {
+	return q.with(func(t *Table[T]) { t.WithContext(ctx) })
+}
+
+// UseTransaction returns the query executed in the transaction
+func (q *Query[T]) UseTransaction(transaction Transaction) *Query[T] {
+	return q.with(func(t *Table[T]) { t.UseTransaction(transaction) })
+}
+
+// UseDBHandle returns the query executed with db
+func (q *Query[T]) UseDBHandle(db DBhandle) *Query[T] {
+	return q.with(func(t *Table[T]) { t.UseDBHandle(db) })
+}
+
+// MustMaster returns the query executed on the master database or not
+func (q *Query[T]) MustMaster(must bool) *Query[T] {
+	return q.with(func(t *Table[T]) { t.MustMaster(must) })
+}
+
+// UseCache returns the query using the default gdaoCache or not
+func (q *Query[T]) UseCache(use bool) *Query[T] {
+	return q.with(func(t *Table[T]) { t.UseCache(use) })
+}
+
+// Unscoped returns the query including the soft deleted rows, see Table.Unscoped
+func (q *Query[T]) Unscoped() *Query[T] {
+	return q.with(func(t *Table[T]) { t.Unscoped() })
+}
+
+// ForUpdate returns the query locking the selected rows for update, see Table.ForUpdate
+func (q *Query[T]) ForUpdate() *Query[T] {
+	return q.with(func(t *Table[T]) { t.ForUpdate() })
+}
+
+// ForShare returns the query locking the selected rows in share mode, see Table.ForShare
+func (q *Query[T]) ForShare() *Query[T] {
+	return q.with(func(t *Table[T]) { t.ForShare() })
+}
+
+// NoWait returns the query failing instead of waiting for the locked rows, see Table.NoWait
+func (q *Query[T]) NoWait() *Query[T] {
+	return q.with(func(t *Table[T]) { t.NoWait() })
+}
+
+// SkipLocked returns the query skipping the locked rows, see Table.SkipLocked
+func (q *Query[T]) SkipLocked() *Query[T] {
+	return q.with(func(t *Table[T]) { t.SkipLocked() })
+}
+
+// Selects executes the query and returns the rows, see Table.Selects
+func (q *Query[T]) Selects(columns ...Column[T]) ([]*T, error) {
+	return q.t.Selects(columns...)
+}
+
+// Select executes the query and returns the first row, see Table.Select
+func (q *Query[T]) Select(columns ...Column[T]) (*T, error) {
+	return q.t.Select(columns...)
+}
+
+// SelectsIter executes the query and returns an iterator streaming the rows, see Table.SelectsIter
+func (q *Query[T]) SelectsIter(columns ...Column[T]) iter.Seq2[*T, error] {
+	return q.t.SelectsIter(columns...)
+}
+
+// SelectsEach executes the query and calls f for each row, see Table.SelectsEach
+func (q *Query[T]) SelectsEach(f func(*T) bool, columns ...Column[T]) error {
+	return q.t.SelectsEach(f, columns...)
+}
+
+// Page executes the query for the page pageNo of pageSize rows and counts its rows, see Table.Page
+func (q *Query[T]) Page(pageNo, pageSize int64, columns ...Column[T]) (*Page[T], error) {
+	return q.t.Page(pageNo, pageSize, columns...)
+}
+
+// Count returns the number of rows of the query, see Table.Count
+func (q *Query[T]) Count() (int64, error) {
+	return q.t.Count()
+}
+
+// Exists reports whether the query selects at least one row
+func (q *Query[T]) Exists() (bool, error) {
+	return q.t.Exists()
+}
+
+// Sum returns the sum of the column over the rows of the query
+func (q *Query[T]) Sum(column Column[T]) (float64, error) {
+	return q.t.Sum(column)
+}
+
+// Avg returns the average of the column over the rows of the query
+func (q *Query[T]) Avg(column Column[T]) (float64, error) {
+	return q.t.Avg(column)
+}
+
+// Max returns the greatest value of the column over the rows of the query
+func (q *Query[T]) Max(column Column[T]) (any, error) {
+	return q.t.Max(column)
+}
+
+// Min returns the least value of the column over the rows of the query
+func (q *Query[T]) Min(column Column[T]) (any, error) {
+	return q.t.Min(column)
+}
+
+// SelectsJoined executes the join query, see Table.SelectsJoined
+func (q *Query[T]) SelectsJoined() ([]*JoinRow[T], error) {
+	return q.t.SelectsJoined()
+}
+
+// SubquerySql returns the select statement of the query and its arguments, see Table.SubquerySql
+func (q *Query[T]) SubquerySql() (string, []any) {
+	return q.t.SubquerySql()
+}
+
+// Subquery returns the query selecting the given columns as a subquery, see Table.Subquery
+func (q *Query[T]) Subquery(columns ...Column[T]) Subquery {
+	return q.t.Subquery(columns...)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func Test_Query(t *testing.T) {
+	useTestDB(t, MYSQL, nil)
+	g := GetDefaultDBHandle()
+	hs := newHstest()
+	o := Alias(newHstest(), "o")
+	base := hs.Query().Where(hs.AGE.GE(18)).OrderBy(hs.ID.Desc())
+	selectSql := func(q *Query[hstest]) (string, []any) {
+		s, args, err := q.t.selectSql(g, q.t.columnNames(q.t.columns))
+		if err != nil {
+			t.Error(err)
+		}
+		return s, args
+	}
+	check := func(s string, args []any, wantSql string, wantArgs ...any) {
+		if s != wantSql || !reflect.DeepEqual(args, wantArgs) {
+			t.Errorf("\n got: %s %v\nwant: %s %v", s, args, wantSql, wantArgs)
+		}
+	}
+	const want = " select id,name,age,version from hstest where age>=? order by id desc "
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limit := int64(i + 1)
+			q := base.Where(hs.NAME.EQ("a")).OrderBy(hs.NAME.Asc()).Limit(limit).InnerJoin(o, hs.ID.EqField(o.ID))
+			s, args := selectSql(q)
+			check(s, args, " select hstest.id,hstest.name,hstest.age,hstest.version from hstest inner join hstest o on id=o.id where name=? order by name asc  LIMIT ? ", "a", limit)
+			s, args = selectSql(base.Limit(limit))
+			check(s, args, want+" LIMIT ? ", 18, limit)
+			s, args = selectSql(base)
+			check(s, args, want, 18)
+		}()
+	}
+	wg.Wait()
+	s, args := selectSql(base)
+	checkSql(t, s, args, want, 18)
+	if len(base.t.joins) != 0 || base.t.limitMode != 0 {
+		t.Fatal(base.t.joins, base.t.limitMode)
+	}
+}
+
+func Test_Clone(t *testing.T) {
+	d := useTestDB(t, MYSQL, nil)
+	hs := newHstest()
+	hs.UseVersion(hs.VERSION)
+	hs.SetVersion(3)
+	c := hs.Clone()
+	c.Put0("name", "a")
+	if _, err := c.Where(hs.ID.EQ(1)).Update(); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d, "update hstest set name=?,version=version+1 where (id=?) and version=?[a 1 3]")
+	if *hs._VERSION != 3 || hs.modifymap["version"] != int64(3) || len(hs.modifymap) != 1 || hs.whereSql != "" {
+		t.Fatal("the clone changes the entity", *hs._VERSION, hs.modifymap, hs.whereSql)
+	}
+	if *c.entity._VERSION != 4 || c.entity.table() != c {
+		t.Fatal("the new version is not written into the copy of the entity", *c.entity._VERSION)
+	}
+
+	if _, err := hs.SetId(1).UpdateByKey(hs.ID); err != nil {
+		t.Fatal(err)
+	}
+	if *hs._VERSION != 4 {
+		t.Fatal("the new version is not written into the entity updated by its key", *hs._VERSION)
+	}
+}
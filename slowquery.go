@@ -0,0 +1,209 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"cmp"
+	. "github.com/donnie4w/gdao/base"
+	"github.com/donnie4w/gdao/util"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// slowQuerySamples is the number of the last durations of a fingerprint kept for the percentiles
+	slowQuerySamples = 512
+	// slowQueryLimit is the number of fingerprints recorded, the slow statements of new fingerprints are ignored beyond it
+	slowQueryLimit = 1 << 10
+)
+
+// SlowQuery is the record of the slow executions of the statements of a datasource sharing a fingerprint
+type SlowQuery struct {
+	// DataSource is the name of the datasource, see NameDataSource
+	DataSource string
+
+	// Fingerprint is the statement normalized by util.Fingerprint
+	Fingerprint string
+
+	// Sql is the last slow statement of the fingerprint
+	Sql string
+
+	// Count is the number of slow executions
+	Count int64
+
+	// Total is the time of all the slow executions
+	Total time.Duration
+
+	// Max is the time of the slowest execution
+	Max time.Duration
+
+	// P50 and P99 are the percentiles of the time of the last slow executions
+	P50, P99 time.Duration
+
+	// Last is the time of the last slow execution
+	Last time.Time
+}
+
+type slowQueryKey struct {
+	dataSource  string
+	fingerprint string
+}
+
+type slowQueryStat struct {
+	SlowQuery
+	samples []time.Duration
+	next    int
+}
+
+var (
+	slowQueryThresholds atomic.Pointer[map[string]time.Duration]
+	slowQueryMux        sync.Mutex
+	slowQueryStats      = make(map[slowQueryKey]*slowQueryStat)
+	slowQueryStop       chan struct{}
+)
+
+// SlowQueryThreshold records the statements of the datasources that take threshold or longer, see SlowQueries.
+// Without dataSources, threshold is the threshold of the datasources that have none.
+// A threshold of 0 removes the threshold. The datasources are named by NameDataSource.
+//
+// Example:
+//
+//	gdao.SlowQueryThreshold(200 * time.Millisecond)
+//	gdao.SlowQueryThreshold(2*time.Second, "report")
+//	gdao.SlowQueryLogInterval(time.Minute)
+func SlowQueryThreshold(threshold time.Duration, dataSources ...string) {
+	slowQueryMux.Lock()
+	defer slowQueryMux.Unlock()
+	thresholds := map[string]time.Duration{}
+	if m := slowQueryThresholds.Load(); m != nil {
+		thresholds = maps.Clone(*m)
+	}
+	if len(dataSources) == 0 {
+		dataSources = []string{""}
+	}
+	for _, dataSource := range dataSources {
+		if threshold > 0 {
+			thresholds[dataSource] = threshold
+		} else {
+			delete(thresholds, dataSource)
+		}
+	}
+	if len(thresholds) == 0 {
+		slowQueryThresholds.Store(nil)
+	} else {
+		slowQueryThresholds.Store(&thresholds)
+	}
+}
+
+// recordSlowQuery records the statement if it took the threshold of its datasource or longer
+func recordSlowQuery(stmt *Statement, d time.Duration) {
+	m := slowQueryThresholds.Load()
+	if m == nil {
+		return
+	}
+	threshold, ok := (*m)[stmt.DataSource]
+	if !ok {
+		if threshold, ok = (*m)[""]; !ok {
+			return
+		}
+	}
+	if d < threshold {
+		return
+	}
+	key := slowQueryKey{stmt.DataSource, util.Fingerprint(stmt.Sql)}
+	slowQueryMux.Lock()
+	defer slowQueryMux.Unlock()
+	stat, ok := slowQueryStats[key]
+	if !ok {
+		if len(slowQueryStats) >= slowQueryLimit {
+			return
+		}
+		stat = &slowQueryStat{SlowQuery: SlowQuery{DataSource: key.dataSource, Fingerprint: key.fingerprint}}
+		slowQueryStats[key] = stat
+	}
+	stat.Sql, stat.Last = stmt.Sql, time.Now()
+	stat.Count++
+	stat.Total += d
+	stat.Max = max(stat.Max, d)
+	if len(stat.samples) < slowQuerySamples {
+		stat.samples = append(stat.samples, d)
+	} else {
+		stat.samples[stat.next] = d
+		stat.next = (stat.next + 1) % slowQuerySamples
+	}
+}
+
+// SlowQueries returns the records of the slow statements, the longest total time first
+func SlowQueries() []SlowQuery {
+	slowQueryMux.Lock()
+	r := make([]SlowQuery, 0, len(slowQueryStats))
+	for _, stat := range slowQueryStats {
+		q := stat.SlowQuery
+		samples := slices.Clone(stat.samples)
+		slices.Sort(samples)
+		q.P50, q.P99 = percentile(samples, 0.5), percentile(samples, 0.99)
+		r = append(r, q)
+	}
+	slowQueryMux.Unlock()
+	slices.SortFunc(r, func(a, b SlowQuery) int {
+		return cmp.Compare(b.Total, a.Total)
+	})
+	return r
+}
+
+// ResetSlowQueries removes the records of the slow statements
+func ResetSlowQueries() {
+	slowQueryMux.Lock()
+	defer slowQueryMux.Unlock()
+	clear(slowQueryStats)
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[max(int(math.Ceil(p*float64(len(sorted))))-1, 0)]
+}
+
+// SlowQueryLogInterval writes the records of the slow statements with base.Logger every interval,
+// when there are new slow executions. An interval of 0 stops the logging.
+func SlowQueryLogInterval(interval time.Duration) {
+	slowQueryMux.Lock()
+	defer slowQueryMux.Unlock()
+	if slowQueryStop != nil {
+		close(slowQueryStop)
+		slowQueryStop = nil
+	}
+	if interval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	slowQueryStop = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var logged time.Time
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				for _, q := range SlowQueries() {
+					if q.Last.After(logged) {
+						Logger.Warnf("[SLOW QUERY][%s][%s] count:%d total:%v max:%v p50:%v p99:%v", q.DataSource, q.Fingerprint, q.Count, q.Total, q.Max, q.P50, q.P99)
+					}
+				}
+				logged = now
+			}
+		}
+	}()
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql"
+	. "github.com/donnie4w/gdao/base"
+	"github.com/donnie4w/gdao/gdaoStruct"
+	"github.com/donnie4w/gdao/util"
+	"github.com/donnie4w/gofer/hashmap"
+	"strings"
+	"time"
+)
+
+// SoftDelete is the column marking the rows of a table as deleted.
+// Delete sets the column to Deleted instead of deleting the rows, and the queries
+// only select the rows whose column holds NotDeleted.
+type SoftDelete struct {
+	// Column is the name of the column
+	Column string
+
+	// Deleted is the value set by Delete, the current time if nil
+	Deleted any
+
+	// NotDeleted is the value of the rows not deleted, null if nil
+	NotDeleted any
+}
+
+// SoftDeleteAt returns the SoftDelete of a timestamp column, such as deleted_at,
+// holding null until the row is deleted and the time of the deletion after.
+func SoftDeleteAt(column string) *SoftDelete {
+	return &SoftDelete{Column: column}
+}
+
+// SoftDeleteFlag returns the SoftDelete of an integer column, such as is_deleted, holding 0 or 1.
+// Use a SoftDelete with the values true and false for a boolean column.
+func SoftDeleteFlag(column string) *SoftDelete {
+	return &SoftDelete{Column: column, Deleted: 1, NotDeleted: 0}
+}
+
+// notDeleted returns the condition selecting the rows not deleted, with the column name, and its arguments
+func (s *SoftDelete) notDeleted(column string) (string, []any) {
+	if s.NotDeleted == nil {
+		return column + " is null", nil
+	}
+	return column + "=?", []any{s.NotDeleted}
+}
+
+func (s *SoftDelete) deleted() any {
+	if s.Deleted == nil {
+		return time.Now()
+	}
+	return s.Deleted
+}
+
+var softDeletes = hashmap.NewMapL[string, *SoftDelete]()
+
+// BindSoftDelete sets the soft delete column of the tables.
+//
+// Example:
+//
+//	gdao.BindSoftDelete(gdao.SoftDeleteAt("deleted_at"), "users", "orders")
+func BindSoftDelete(softDelete *SoftDelete, tableNames ...string) {
+	for _, tableName := range tableNames {
+		softDeletes.Put(tableName, softDelete)
+	}
+}
+
+// BindSoftDeleteWithClass sets the soft delete column of the standardized entity class generated by gdao.
+//
+// Example:
+//
+//	gdao.BindSoftDeleteWithClass[dao.Hstest](gdao.SoftDeleteFlag("is_deleted"))
+func BindSoftDeleteWithClass[T gdaoStruct.TableClass](softDelete *SoftDelete) {
+	softDeletes.Put(util.Classname[T](), softDelete)
+}
+
+func UnbindSoftDelete(tableNames ...string) {
+	for _, tableName := range tableNames {
+		softDeletes.Del(tableName)
+	}
+}
+
+func UnbindSoftDeleteWithClass[T gdaoStruct.TableClass]() {
+	softDeletes.Del(util.Classname[T]())
+}
+
+// UseSoftDelete sets the soft delete column of the table, overriding BindSoftDelete and BindSoftDeleteWithClass.
+// Delete then sets the column instead of deleting the rows, and Select, Selects, Count and the other
+// queries exclude the deleted rows, unless Unscoped is called. HardDelete deletes the rows.
+// The entity classes built with gdaoBuilder.WithSoftDelete call UseSoftDelete when they are created.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.UseSoftDelete(gdao.SoftDeleteAt("deleted_at"))
+//	hs.Where(hs.Id.EQ(1)).Delete()
+//	// update hstest set deleted_at=? where (id=?) and deleted_at is null
+func (t *Table[T]) UseSoftDelete(softDelete *SoftDelete) {
+	t.softDelete = softDelete
+}
+
+// Unscoped includes the soft deleted rows in the queries and the updates of the Table, until Reset is called
+func (t *Table[T]) Unscoped() *Table[T] {
+	t.unscoped = true
+	return t
+}
+
+// getSoftDelete returns the soft delete column of the table, nil if there is none
+func (t *Table[T]) getSoftDelete() *SoftDelete {
+	if t.softDelete != nil {
+		return t.softDelete
+	}
+	if s, ok := bound(softDeletes, t.getClassname(), t.tableName); ok {
+		return s
+	}
+	return nil
+}
+
+// whereClause returns the where clause of the query excluding the soft deleted rows, and its arguments
+func (t *Table[T]) whereClause() (string, []any) {
+	return t.scopedWhere(t.columnName)
+}
+
+// scopedWhere returns the where clause excluding the soft deleted rows,
+// with the soft delete column named by columnName, and its arguments
+func (t *Table[T]) scopedWhere(columnName func(string) string) (string, []any) {
+	s := t.getSoftDelete()
+	if s == nil || t.unscoped {
+		return t.whereSql, t.whereArgs
+	}
+	condition, args := s.notDeleted(columnName(s.Column))
+	if t.whereSql == "" {
+		return " where " + condition, args
+	}
+	return " where (" + strings.TrimPrefix(t.whereSql, " where ") + ") and " + condition, append(append([]any{}, t.whereArgs...), args...)
+}
+
+// softDeleteExec sets the soft delete column of the rows of the conditions
+func (t *Table[T]) softDeleteExec(s *SoftDelete) (sql.Result, error) {
+	condition, conditionArgs := t.conditionSql()
+	sqlstr := "update " + t.tableName + " set " + s.Column + "=?" + condition
+	args := append([]any{s.deleted()}, conditionArgs...)
+
+	if Logger.IsVaild {
+		Logger.Debug("[SOFT DELETE]["+sqlstr+"]", args)
+	}
+
+	if g := t.getDB(false); g != nil {
+		t.clearExpire()
+		return g.ExecuteUpdateContext(t.getContext(), sqlstr, args...)
+	} else {
+		return nil, errInit
+	}
+}
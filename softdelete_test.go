@@ -0,0 +1,80 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_scopedWhere(t *testing.T) {
+	hs := newHstest()
+	where, args := hs.whereClause()
+	checkSql(t, where, args, "")
+
+	hs.UseSoftDelete(SoftDeleteAt("deleted_at"))
+	where, args = hs.whereClause()
+	checkSql(t, where, args, " where deleted_at is null")
+
+	hs.Where(hs.ID.EQ(1), hs.NAME.EQ("a"))
+	where, args = hs.whereClause()
+	checkSql(t, where, args, " where (id=? and name=?) and deleted_at is null", 1, "a")
+	where, args = hs.scopedWhere(func(name string) string { return qualify("h", name) })
+	checkSql(t, where, args, " where (id=? and name=?) and h.deleted_at is null", 1, "a")
+
+	hs.UseSoftDelete(SoftDeleteFlag("is_deleted"))
+	where, args = hs.whereClause()
+	checkSql(t, where, args, " where (id=? and name=?) and is_deleted=?", 1, "a", 0)
+	if len(hs.whereArgs) != 2 {
+		t.Fatal("the arguments of the conditions are modified", hs.whereArgs)
+	}
+
+	hs.Unscoped()
+	where, args = hs.whereClause()
+	checkSql(t, where, args, " where id=? and name=?", 1, "a")
+	hs.Reset()
+	where, args = hs.whereClause()
+	checkSql(t, where, args, " where is_deleted=?", 0)
+}
+
+func Test_SoftDelete(t *testing.T) {
+	d := useTestDB(t, MYSQL, []string{"id"})
+	hs := newHstest()
+	hs.UseSoftDelete(SoftDeleteFlag("is_deleted"))
+	if _, err := hs.Where(hs.ID.EQ(1)).Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hs.Selects(hs.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hs.HardDelete(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hs.Unscoped().Selects(hs.ID); err != nil {
+		t.Fatal(err)
+	}
+	checkStatements(t, d,
+		"update hstest set is_deleted=? where (id=?) and is_deleted=?[1 1 0]",
+		" select id from hstest where (id=?) and is_deleted=?[1 0]",
+		" delete from hstest where id=?[1]",
+		" select id from hstest where id=?[1]")
+
+	BindSoftDelete(SoftDeleteAt("deleted_at"), "hstest")
+	t.Cleanup(func() { UnbindSoftDelete("hstest") })
+	hs = newHstest()
+	if _, err := hs.Where(hs.ID.EQ(2)).Delete(); err != nil {
+		t.Fatal(err)
+	}
+	s := d.statements()
+	if len(s) != 1 || s[0].sql != "update hstest set deleted_at=? where (id=?) and deleted_at is null" {
+		t.Fatal(s)
+	}
+	if _, ok := s[0].args[0].(time.Time); !ok || s[0].args[1] != 2 {
+		t.Fatal(s[0].args)
+	}
+}
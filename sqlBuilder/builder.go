@@ -8,8 +8,10 @@
 package sqlBuilder
 
 import (
+	"context"
 	"database/sql"
 	"github.com/donnie4w/gdao/base"
+	"iter"
 )
 
 // SqlBuilder is an interface used for building dynamic SQL queries.
@@ -29,6 +31,11 @@ type SqlBuilder interface {
 	//   tx: An object that implements the base.Transaction interface, providing methods to start, commit, and rollback a transaction.
 	UseTransaction(transaction base.Transaction)
 
+	// WithContext sets the context used when executing the built SQL statement,
+	// so that cancellation and deadlines of ctx reach the database driver.
+	// Returns the SqlBuilder instance itself, supporting method chaining.
+	WithContext(ctx context.Context) SqlBuilder
+
 	// Append appends a piece of text to the current SQL statement.
 	// The parameter text is the string to append.
 	// The parameter params is a variadic list of values that may be needed for subsequent parameters.
@@ -90,6 +97,14 @@ type SqlBuilder interface {
 	// Returns a *base.DataBeans object representing all records of the query result.
 	SelectList() *base.DataBeans
 
+	// SelectIter executes a SQL query and returns an iterator streaming the records one at a time.
+	// The underlying rows stay open while iterating and are closed when the loop finishes or breaks early.
+	SelectIter() iter.Seq2[*base.DataBean, error]
+
+	// SelectEach executes a SQL query and calls f for each record as it is read.
+	// The iteration stops when f returns false. Returns the first error encountered.
+	SelectEach(f func(*base.DataBean) bool) error
+
 	// Exec executes a SQL statement and returns the number of affected rows.
 	// Returns an int64 value representing the number of affected rows.
 	// If there is an error during execution, it also returns the corresponding error information.
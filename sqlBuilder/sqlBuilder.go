@@ -8,10 +8,12 @@
 package sqlBuilder
 
 import (
+	"context"
 	"database/sql"
 	"github.com/donnie4w/gdao"
 	"github.com/donnie4w/gdao/base"
 	"github.com/donnie4w/gdao/util"
+	"iter"
 	"strings"
 )
 
@@ -20,6 +22,7 @@ type sqlBuilder struct {
 	parameters []any
 	dbhandle   base.DBhandle
 	tx         base.Transaction
+	ctx        context.Context
 }
 
 func NewSqlBuilder() SqlBuilder {
@@ -34,6 +37,18 @@ func (b *sqlBuilder) UseTransaction(transaction base.Transaction) {
 	b.tx = transaction
 }
 
+func (b *sqlBuilder) WithContext(ctx context.Context) SqlBuilder {
+	b.ctx = ctx
+	return b
+}
+
+func (b *sqlBuilder) getContext() context.Context {
+	if b.ctx != nil {
+		return b.ctx
+	}
+	return context.Background()
+}
+
 func (b *sqlBuilder) Append(text string, params ...any) SqlBuilder {
 	return b.append(text, params...)
 }
@@ -179,23 +194,42 @@ func (b *sqlBuilder) SelectOne() *base.DataBean {
 		base.Logger.Debug("[SqlBuilder SQL]", b.GetSql(), "[ARGS]", b.GetParameters())
 	}
 	if b.dbhandle != nil {
-		return b.dbhandle.ExecuteQueryBean(b.GetSql(), b.GetParameters()...)
+		return b.dbhandle.ExecuteQueryBeanContext(b.getContext(), b.GetSql(), b.GetParameters()...)
 	}
-	return gdao.ExecuteQueryBean(b.GetSql(), b.GetParameters()...)
+	return gdao.ExecuteQueryBeanContext(b.getContext(), b.GetSql(), b.GetParameters()...)
 }
 
 func (b *sqlBuilder) SelectList() *base.DataBeans {
 	if base.Logger.IsVaild {
 		base.Logger.Debug("[SqlBuilder SQL]", b.GetSql(), "[ARGS]", b.GetParameters())
 	}
-	return b.getDBHandle().ExecuteQueryBeans(b.GetSql(), b.GetParameters()...)
+	return b.getDBHandle().ExecuteQueryBeansContext(b.getContext(), b.GetSql(), b.GetParameters()...)
+}
+
+func (b *sqlBuilder) SelectIter() iter.Seq2[*base.DataBean, error] {
+	if base.Logger.IsVaild {
+		base.Logger.Debug("[SqlBuilder SQL]", b.GetSql(), "[ARGS]", b.GetParameters())
+	}
+	return b.getDBHandle().ExecuteQueryIter(b.getContext(), b.GetSql(), b.GetParameters()...)
+}
+
+func (b *sqlBuilder) SelectEach(f func(*base.DataBean) bool) error {
+	for bean, err := range b.SelectIter() {
+		if err != nil {
+			return err
+		}
+		if !f(bean) {
+			break
+		}
+	}
+	return nil
 }
 
 func (b *sqlBuilder) Exec() (sql.Result, error) {
 	if base.Logger.IsVaild {
 		base.Logger.Debug("[SqlBuilder SQL]", b.GetSql(), "[ARGS]", b.GetParameters())
 	}
-	return b.getDBHandle().ExecuteUpdate(b.GetSql(), b.GetParameters()...)
+	return b.getDBHandle().ExecuteUpdateContext(b.getContext(), b.GetSql(), b.GetParameters()...)
 }
 
 func (b *sqlBuilder) getDBHandle() (r base.DBhandle) {
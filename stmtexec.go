@@ -8,6 +8,7 @@
 package gdao
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	. "github.com/donnie4w/gdao/base"
@@ -28,19 +29,19 @@ type stmtexec struct {
 	lock    int64
 }
 
-func (se *stmtexec) Exec(db *sql.DB, sqlStr string, args ...any) (rs sql.Result, err error) {
-	if stmt, e := se.Prepare(sqlStr, db); e == nil {
-		return stmt.Exec(args...)
+func (se *stmtexec) Exec(ctx context.Context, db *sql.DB, sqlStr string, args ...any) (rs sql.Result, err error) {
+	if stmt, e := se.Prepare(ctx, sqlStr, db); e == nil {
+		return stmt.ExecContext(ctx, args...)
 	} else {
-		return db.Exec(sqlStr, args...)
+		return db.ExecContext(ctx, sqlStr, args...)
 	}
 }
 
-func (se *stmtexec) Qurey(db *sql.DB, sqlStr string, args ...any) (rs *sql.Rows, err error) {
-	if stmt, e := se.Prepare(sqlStr, db); e == nil {
-		return stmt.Query(args...)
+func (se *stmtexec) Qurey(ctx context.Context, db *sql.DB, sqlStr string, args ...any) (rs *sql.Rows, err error) {
+	if stmt, e := se.Prepare(ctx, sqlStr, db); e == nil {
+		return stmt.QueryContext(ctx, args...)
 	} else {
-		return db.Query(sqlStr, args...)
+		return db.QueryContext(ctx, sqlStr, args...)
 	}
 }
 
@@ -49,11 +50,18 @@ func (se *stmtexec) clear(db *sql.DB) {
 		defer atomic.StoreInt64(&se.lock, 0)
 		if sm, _ := se.stmtMap.Get(db); sm != nil {
 			if sm.Len() >= stmtLimit {
+				n := int64(0)
 				sm.Range(func(k uint64, v *sql.Stmt) bool {
 					sm.Del(k)
 					v.Close()
+					n++
 					return true
 				})
+				if MetricsEnabled() {
+					name := dataSourceName(db)
+					AddCounter(MetricStmtCacheEvictions, n, LabelDataSource, name)
+					SetGauge(MetricStmtCacheSize, sm.Len(), LabelDataSource, name)
+				}
 			}
 		}
 	}
@@ -69,7 +77,7 @@ func (se *stmtexec) newmap(db *sql.DB) (r *hashmap.MapL[uint64, *sql.Stmt]) {
 	return r
 }
 
-func (se *stmtexec) Prepare(sqlStr string, db *sql.DB) (stmt *sql.Stmt, err error) {
+func (se *stmtexec) Prepare(ctx context.Context, sqlStr string, db *sql.DB) (stmt *sql.Stmt, err error) {
 	if se.len(db) >= stmtLimit {
 		se.clear(db)
 		return stmt, errorStmt
@@ -79,22 +87,35 @@ func (se *stmtexec) Prepare(sqlStr string, db *sql.DB) (stmt *sql.Stmt, err erro
 	if hm, _ = se.stmtMap.Get(db); hm != nil {
 		sqlhs := goutil.Hash64([]byte(sqlStr))
 		if a, b := hm.Get(sqlhs); b {
+			stmtCacheMetric(MetricStmtCacheHits, db, 1)
 			return a, nil
 		}
 	} else {
 		hm = se.newmap(db)
 	}
-	if stmt, err = db.Prepare(sqlStr); err == nil {
+	stmtCacheMetric(MetricStmtCacheMisses, db, 1)
+	if stmt, err = db.PrepareContext(ctx, sqlStr); err == nil {
 		if sqlhs == 0 {
 			sqlhs = goutil.Hash64([]byte(sqlStr))
 		}
 		if p, ok := hm.Put(sqlhs, stmt); ok && p != nil {
 			p.Close()
+			stmtCacheMetric(MetricStmtCacheEvictions, db, 1)
+		}
+		if MetricsEnabled() {
+			SetGauge(MetricStmtCacheSize, hm.Len(), LabelDataSource, dataSourceName(db))
 		}
 	}
 	return
 }
 
+// stmtCacheMetric adds delta to the counter of the prepared statements cache of db
+func stmtCacheMetric(name string, db *sql.DB, delta int64) {
+	if MetricsEnabled() {
+		AddCounter(name, delta, LabelDataSource, dataSourceName(db))
+	}
+}
+
 func (se *stmtexec) len(db *sql.DB) int64 {
 	if se.lock > 0 {
 		return se.lock
@@ -119,18 +140,18 @@ func (se *stmtexec) nostmt(tx *sql.Tx, db *sql.DB, sqlstr string) (b bool) {
 	return
 }
 
-func (se *stmtexec) executeQueryBeans(tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (databases []*DataBean, err error) {
+func (se *stmtexec) executeQueryBeans(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (databases []*DataBean, err error) {
 	if se.nostmt(tx, db, sqlstr) {
-		return executeQueryBeans(tx, db, sqlstr, args...)
+		return executeQueryBeans(ctx, tx, db, sqlstr, args...)
 	}
 	if tx == nil && db == nil {
 		return nil, errInit
 	}
 	var rows *sql.Rows
 	if tx != nil {
-		return executeQueryBeans(tx, db, sqlstr, args...)
+		return executeQueryBeans(ctx, tx, db, sqlstr, args...)
 	} else {
-		rows, err = se.Qurey(db, sqlstr, args...)
+		rows, err = se.Qurey(ctx, db, sqlstr, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -159,18 +180,18 @@ func (se *stmtexec) executeQueryBeans(tx *sql.Tx, db *sql.DB, sqlstr string, arg
 	return
 }
 
-func (se *stmtexec) executeQueryBean(tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (dataBean *DataBean, err error) {
+func (se *stmtexec) executeQueryBean(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (dataBean *DataBean, err error) {
 	if se.nostmt(tx, db, sqlstr) {
-		return executeQueryBean(tx, db, sqlstr, args...)
+		return executeQueryBean(ctx, tx, db, sqlstr, args...)
 	}
 	if tx == nil && db == nil {
 		return nil, errInit
 	}
 	var rows *sql.Rows
 	if tx != nil {
-		return executeQueryBean(tx, db, sqlstr, args...)
+		return executeQueryBean(ctx, tx, db, sqlstr, args...)
 	} else {
-		rows, err = se.Qurey(db, sqlstr, args...)
+		rows, err = se.Qurey(ctx, db, sqlstr, args...)
 	}
 	if err != nil {
 		return nil, err
@@ -197,17 +218,27 @@ func (se *stmtexec) executeQueryBean(tx *sql.Tx, db *sql.DB, sqlstr string, args
 	return
 }
 
-func (se *stmtexec) executeUpdate(tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (rs sql.Result, err error) {
+func (se *stmtexec) executeUpdate(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (rs sql.Result, err error) {
 	if se.nostmt(tx, db, sqlstr) {
-		return executeUpdate(tx, db, sqlstr, args...)
+		return executeUpdate(ctx, tx, db, sqlstr, args...)
 	}
 	if tx == nil && db == nil {
 		return nil, errInit
 	}
 	defer util.Recover(&err)
 	if tx != nil {
-		return executeUpdate(tx, db, sqlstr, args...)
+		return executeUpdate(ctx, tx, db, sqlstr, args...)
 	} else {
-		return se.Exec(db, sqlstr, args...)
+		return se.Exec(ctx, db, sqlstr, args...)
+	}
+}
+
+func (se *stmtexec) executeQueryRows(ctx context.Context, tx *sql.Tx, db *sql.DB, sqlstr string, args ...any) (*sql.Rows, error) {
+	if se.nostmt(tx, db, sqlstr) || tx != nil {
+		return executeQueryRows(ctx, tx, db, sqlstr, args...)
+	}
+	if db == nil {
+		return nil, errInit
 	}
+	return se.Qurey(ctx, db, sqlstr, args...)
 }
@@ -8,11 +8,16 @@
 package gdao
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	. "github.com/donnie4w/gdao/base"
 	"github.com/donnie4w/gdao/gdaoCache"
 	"github.com/donnie4w/gdao/gdaoStruct"
 	"github.com/donnie4w/gdao/util"
+	"iter"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -21,40 +26,91 @@ type Table[T any] struct {
 	gdaoStruct.TableClass
 	commentline string
 	tableName   string
-	querySql    string
 	whereSql    string
-	args        []any
+	whereArgs   []any
+	whereOp     string
 	groupSql    string
 	havingSql   string
+	havingArgs  []any
 	orderSql    string
-	limitSql    string
-	sql         string
+	limit       int64
+	offset      int64
+	limitMode   int8
+	lockMode    int8
+	lockWait    int8
 	modifymap   map[string]any
-	batchmap    map[string][]any
-	modifySql   string
+	loaded      map[string]any
+	batchrows   []map[string]any
 	dbhandler   DBhandle
 	transaction Transaction
-	batchArgs   [][]any
 	mustMaster  bool
 	isCache     int8
+	multiRow    int8
+	upsert      []string
 	classname   string
 	columns     []Column[T]
-}
-
-func (t *Table[T]) Init(s string, columns []Column[T]) {
+	ctx         context.Context
+	alias       string
+	qualifier   string
+	joins       []*join
+	version     string
+	identity    string
+	entity      *T
+	softDelete  *SoftDelete
+	unscoped    bool
+	audit       *Audit
+}
+
+// Init initializes the Table of a standardized entity class with the table name and its columns.
+// The generated entity classes pass themselves as entity, so that the values written by gdao,
+// such as a new version, are set on them.
+func (t *Table[T]) Init(s string, columns []Column[T], entity ...*T) {
 	t.tableName = s
-	t.modifymap = map[string]any{}
+	t.modifymap, t.loaded = map[string]any{}, nil
 	t.columns = columns
+	t.classname = util.Classname[T]()
+	if len(entity) > 0 {
+		t.entity = entity[0]
+	}
 }
 
 func (t *Table[T]) IsInit() bool {
-	return t.tableName != "" && t.args != nil && t.modifymap != nil
+	return t.tableName != "" && t.modifymap != nil
 }
 
 func (t *Table[T]) Put0(k string, v any) {
+	if t.qualifier != "" {
+		k = strings.TrimPrefix(k, t.qualifier+".")
+	}
 	t.modifymap[k] = v
 }
 
+// Loaded keeps the values set on the entity as the values loaded from its row, which are not written
+// by Update, see base.Loader. The entities scanned by gdao are loaded.
+func (t *Table[T]) Loaded() {
+	if len(t.modifymap) > 0 {
+		t.loaded, t.modifymap = t.modifymap, map[string]any{}
+	}
+}
+
+// value returns the value of the column set on the entity, or else loaded from its row
+func (t *Table[T]) value(column string) (v any, ok bool) {
+	if v, ok = t.modifymap[column]; !ok {
+		v, ok = t.loaded[column]
+	}
+	return
+}
+
+// values returns the values loaded from the row of the entity and the values set on it
+func (t *Table[T]) values() map[string]any {
+	if len(t.loaded) == 0 {
+		return t.modifymap
+	}
+	values := maps.Clone(t.loaded)
+	maps.Copy(values, t.modifymap)
+	return values
+}
+
 func (t *Table[T]) UseCache(use bool) {
 	if use {
 		t.isCache = 1
@@ -63,6 +119,40 @@ func (t *Table[T]) UseCache(use bool) {
 	}
 }
 
+// UseMultiRowBatch sets whether ExecBatch sends the rows as multi-row inserts,
+// overriding the default set by MultiRowBatch.
+func (t *Table[T]) UseMultiRowBatch(use bool) {
+	if use {
+		t.multiRow = 1
+	} else {
+		t.multiRow = 2
+	}
+}
+
+// UseUpsert sets ExecBatch to upsert the rows added by AddBatch on the conflict columns
+// instead of inserting them, see Upsert.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.UseUpsert(hs.Id)
+//	for id, name := range names {
+//	    hs.SetId(id)
+//	    hs.SetName(name)
+//	    hs.AddBatch()
+//	}
+//	hs.ExecBatch()
+func (t *Table[T]) UseUpsert(conflictColumns ...Column[T]) {
+	t.upsert = t.conflictNames(conflictColumns)
+}
+
+func (t *Table[T]) executeBatch(g DBhandle, sqlstr string, args [][]any) ([]sql.Result, error) {
+	if (t.multiRow == 1 || multiRowBatch) && t.multiRow != 2 {
+		return executeBatchMultiRow(t.getContext(), g, sqlstr, args)
+	}
+	return g.ExecuteBatchContext(t.getContext(), sqlstr, args)
+}
+
 // Where adds a WHERE clause to the query with one or more conditions.
 //
 // Parameters:
@@ -78,6 +168,8 @@ func (t *Table[T]) UseCache(use bool) {
 //	This function allows you to specify one or more conditions that will be added to the WHERE clause of the SQL query.
 //	Each *Where[T] object represents a condition that must be satisfied by the rows returned by the query.
 //	Multiple conditions can be combined to form complex queries.
+//	The conditions are joined with and, a condition holding an or is put in parentheses.
+//	Nested conditions are built with gdao.And, gdao.Or and gdao.Not.
 //
 // Example:
 //
@@ -87,25 +179,32 @@ func (t *Table[T]) UseCache(use bool) {
 //	hs = hs.Where(hs.Rowname.RLIKE(1)).GroupBy(hs.Id).Having(hs.Id.Count().LT(2)).Limit(2)
 //	hslist, _ := hs.Selects()
 func (t *Table[T]) Where(wheres ...*Where[T]) *Table[T] {
-	builder := strings.Builder{}
-	for i, w := range wheres {
-		builder.WriteString(w.WhereSql)
-		if i < len(wheres)-1 {
-			builder.WriteString(" and ")
-		}
-		if w.Value != nil {
-			t.args = append(t.args, w.Value)
-		}
-		if w.Values != nil {
-			for _, v := range w.Values {
-				t.args = append(t.args, v)
-			}
-		}
-	}
-	t.whereSql = " where " + builder.String()
+	w := WhereJoin("and", wheres...)
+	t.whereSql = " where " + w.WhereSql
+	t.whereArgs, t.whereOp = w.Values, w.Op()
 	return t
 }
 
+// WithContext sets the context used by the statements executed through the Table,
+// so that cancellation and deadlines of ctx reach the database driver.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.WithContext(r.Context()).Where(hs.Id.GT(10))
+//	hslist, err := hs.Selects()
+func (t *Table[T]) WithContext(ctx context.Context) *Table[T] {
+	t.ctx = ctx
+	return t
+}
+
+func (t *Table[T]) getContext() context.Context {
+	if t.ctx != nil {
+		return t.ctx
+	}
+	return context.Background()
+}
+
 func (t *Table[T]) UseTransaction(transaction Transaction) {
 	t.transaction = transaction
 }
@@ -120,141 +219,147 @@ func (t *Table[T]) UseDBHandle(db DBhandle) *Table[T] {
 }
 
 func (t *Table[T]) executeQueryList(columns ...Column[T]) (_r []*T, err error) {
-	t.completeSql4Columns(columns...)
-	t.completeSql4Query()
+	g := t.getDB(true)
+	if g == nil {
+		return nil, errInit
+	}
+	sqlstr, args, err := t.selectSql(g, t.columnNames(columns))
+	if err != nil {
+		return nil, err
+	}
 
 	if Logger.IsVaild {
-		Logger.Debug("[SELETE LIST]["+t.sql+"]", t.args)
-	}
-	if t.classname == "" {
-		t.classname = util.Classname[T]()
+		Logger.Debug("[SELETE LIST]["+sqlstr+"]", args)
 	}
-	domain := gdaoCache.GetDomain(t.classname, t.tableName)
-	iscache := (t.isCache == 1 || domain != "") && t.isCache != 2
+	classname := t.getClassname()
+	domain, iscache := t.useCache()
 	var condition *gdaoCache.Condition
 	if iscache {
-		condition = gdaoCache.NewCondition("[]*"+t.classname, t.sql, t.args...)
-		if result := gdaoCache.GetCache(domain, t.classname, condition); result != nil {
+		condition = gdaoCache.NewCondition("[]*"+classname, sqlstr, args...)
+		if result := gdaoCache.GetCacheContext(t.getContext(), domain, classname, condition); result != nil {
 			if Logger.IsVaild {
-				Logger.Debug("[GET CACHE]["+t.sql+"]", t.args)
+				Logger.Debug("[GET CACHE]["+sqlstr+"]", args)
 			}
 			return result.([]*T), nil
 		}
 	}
 
-	if g := t.getDB(true); g != nil {
-		if databeans := g.ExecuteQueryBeans(t.sql, t.args...); databeans.GetError() == nil && databeans.Len() > 0 {
-			_r = make([]*T, 0)
-			for _, bean := range databeans.Beans {
-				t := new(T)
-				if err = bean.ScanAndFree(t); err == nil {
-					_r = append(_r, t)
-				} else {
-					break
-				}
+	if databeans := g.ExecuteQueryBeansContext(t.getContext(), sqlstr, args...); databeans.GetError() == nil && databeans.Len() > 0 {
+		_r = make([]*T, 0)
+		for _, bean := range databeans.Beans {
+			t := new(T)
+			if err = bean.ScanAndFree(t); err == nil {
+				_r = append(_r, t)
+			} else {
+				break
 			}
-			if iscache {
-				gdaoCache.SetCache(domain, t.classname, condition, _r)
-				if Logger.IsVaild {
-					Logger.Debug("[SET CACHE]["+t.sql+"]", t.args)
-				}
+		}
+		if iscache {
+			gdaoCache.SetCache(domain, classname, condition, _r)
+			if Logger.IsVaild {
+				Logger.Debug("[SET CACHE]["+sqlstr+"]", args)
 			}
-		} else {
-			err = databeans.GetError()
 		}
-		return
 	} else {
-		return nil, errInit
+		err = databeans.GetError()
 	}
+	return
 }
 
 func (t *Table[T]) executeQuery(columns ...Column[T]) (_r *T, err error) {
-	t.completeSql4Columns(columns...)
-	t.completeSql4Query()
+	g := t.getDB(true)
+	if g == nil {
+		return nil, errInit
+	}
+	sqlstr, args, err := t.selectSql(g, t.columnNames(columns))
+	if err != nil {
+		return nil, err
+	}
 
 	if Logger.IsVaild {
-		Logger.Debug("[SELETE ONE]["+t.sql+"]", t.args)
-	}
-	if t.classname == "" {
-		t.classname = util.Classname[T]()
+		Logger.Debug("[SELETE ONE]["+sqlstr+"]", args)
 	}
-	domain := gdaoCache.GetDomain(t.classname, t.tableName)
-	iscache := (t.isCache == 1 || domain != "") && t.isCache != 2
+	classname := t.getClassname()
+	domain, iscache := t.useCache()
 	var condition *gdaoCache.Condition
 	if iscache {
-		condition = gdaoCache.NewCondition("*"+t.classname, t.sql, t.args...)
-		if result := gdaoCache.GetCache(domain, t.classname, condition); result != nil {
+		condition = gdaoCache.NewCondition("*"+classname, sqlstr, args...)
+		if result := gdaoCache.GetCacheContext(t.getContext(), domain, classname, condition); result != nil {
 			if Logger.IsVaild {
-				Logger.Debug("[GET CACHE]["+t.sql+"]", t.args)
+				Logger.Debug("[GET CACHE]["+sqlstr+"]", args)
 			}
 			return result.(*T), nil
 		}
 	}
 
-	if g := t.getDB(true); g != nil {
-		if bean := g.ExecuteQueryBean(t.sql, t.args...); bean.GetError() == nil && bean.Len() > 0 {
-			_r = new(T)
-			if err = bean.ScanAndFree(_r); err == nil {
-				if iscache {
-					gdaoCache.SetCache(domain, t.classname, condition, _r)
-					if Logger.IsVaild {
-						Logger.Debug("[SET CACHE]["+t.sql+"]", t.args)
-					}
+	if bean := g.ExecuteQueryBeanContext(t.getContext(), sqlstr, args...); bean.GetError() == nil && bean.Len() > 0 {
+		_r = new(T)
+		if err = bean.ScanAndFree(_r); err == nil {
+			if iscache {
+				gdaoCache.SetCache(domain, classname, condition, _r)
+				if Logger.IsVaild {
+					Logger.Debug("[SET CACHE]["+sqlstr+"]", args)
 				}
 			}
-		} else {
-			err = bean.GetError()
 		}
-		return
 	} else {
-		return nil, errInit
+		err = bean.GetError()
 	}
+	return
 }
 
-func (t *Table[T]) completeSql4Columns(columns ...Column[T]) {
-	querycolumns := make([]string, len(columns))
-	for i, c := range columns {
-		name := c.Name()
-		querycolumns[i] = name
-	}
-	s := strings.Join(querycolumns, ",")
-	t.querySql = t.commentline + " select " + s + " from " + t.tableName
+// useCache returns the cache domain of the table and whether the query uses the cache
+func (t *Table[T]) useCache() (string, bool) {
+	domain := gdaoCache.GetDomain(t.getClassname(), t.tableName)
+	return domain, (t.isCache == 1 || domain != "") && t.isCache != 2 && len(t.joins) == 0 && t.lockMode == 0
 }
 
-func (t *Table[T]) completeSql4Query() {
-	t.sql = t.querySql
-	if t.sql != "" {
-		if t.whereSql != "" {
-			t.sql = t.sql + t.whereSql
-		}
-		if t.groupSql != "" {
-			t.sql = t.sql + t.groupSql
-		}
-		if t.havingSql != "" {
-			t.sql = t.sql + t.havingSql
-		}
-		if t.orderSql != "" {
-			t.sql = t.sql + t.orderSql
-		}
-		if t.limitSql != "" {
-			t.sql = t.sql + t.limitSql
-		}
+func (t *Table[T]) getClassname() string {
+	if t.classname != "" {
+		return t.classname
 	}
+	return util.Classname[T]()
 }
 
-func (t *Table[T]) completeSql4Update() {
-	t.sql = t.modifySql
-	if t.sql != "" {
-		if t.whereSql != "" {
-			t.sql = t.sql + t.whereSql
-		}
-		if t.groupSql != "" {
-			t.sql = t.sql + t.groupSql
-		}
-		if t.havingSql != "" {
-			t.sql = t.sql + t.havingSql
+func (t *Table[T]) columnNames(columns []Column[T]) []string {
+	querycolumns := make([]string, len(columns))
+	for i, c := range columns {
+		querycolumns[i] = t.columnName(c.Name())
+	}
+	return querycolumns
+}
+
+// selectSql returns the select statement of the columns with the conditions, grouping,
+// ordering, limit and row locking of the table, and its arguments. The Table is not modified,
+// so the same query can be executed again or from several goroutines.
+func (t *Table[T]) selectSql(g DBhandle, querycolumns []string) (string, []any, error) {
+	clause, err := t.limitClause(g)
+	if err != nil {
+		return "", nil, err
+	}
+	lock, err := t.lockClause(g)
+	if err != nil {
+		return "", nil, err
+	}
+	where, _ := t.whereClause()
+	if lock.KeyColumn != "" && t.limitMode != 0 {
+		key := t.columnName(lock.KeyColumn)
+		subquery := "select " + clause.Top + key + " from " + t.fromSql() + where + t.groupSql + t.havingSql + t.orderSql + clause.Sql
+		s := t.commentline + " select " + strings.Join(querycolumns, ",") + " from " + t.fromSqlHint(lock.Hint) + " where " + key + " in (" + subquery + ")" + t.orderSql + lock.Sql
+		var args []any
+		for _, j := range t.joins {
+			args = append(args, j.args...)
 		}
+		return s, append(append(args, t.queryArgs()...), clause.Args...), nil
 	}
+	s := t.commentline + " select " + clause.Top + strings.Join(querycolumns, ",") + " from " + t.fromSqlHint(lock.Hint) + where + t.groupSql + t.havingSql + t.orderSql + clause.Sql + lock.Sql
+	return s, append(t.queryArgs(), clause.Args...), nil
+}
+
+// conditionSql returns the where, group by and having clauses of an update or delete statement and their arguments
+func (t *Table[T]) conditionSql() (string, []any) {
+	where, args := t.whereClause()
+	return where + t.groupSql + t.havingSql, append(append([]any{}, args...), t.havingArgs...)
 }
 
 func (t *Table[T]) getDB(queryType bool) (r DBhandle) {
@@ -278,18 +383,18 @@ func (t *Table[T]) GroupBy(columns ...Column[T]) *Table[T] {
 
 func (t *Table[T]) Having(havings ...*Having[T]) *Table[T] {
 	ss := make([]string, 0, len(havings))
+	args := make([]any, 0, len(havings))
 	for _, w := range havings {
 		ss = append(ss, w.HavingSql)
 		if w.Value != nil {
-			t.args = append(t.args, w.Value)
+			args = append(args, w.Value)
 		}
 		if w.Values != nil {
-			for _, v := range w.Values {
-				t.args = append(t.args, v)
-			}
+			args = append(args, w.Values...)
 		}
 	}
 	t.havingSql = " having " + strings.Join(ss, ",")
+	t.havingArgs = args
 	return t
 }
 
@@ -304,61 +409,25 @@ func (t *Table[T]) OrderBy(sorts ...*Sort[T]) *Table[T] {
 
 func (t *Table[T]) Limit(limit int64) {
 	if limit > 0 {
-		t.limitAdapt(limit)
+		t.limit, t.offset, t.limitMode = limit, 0, 1
 	}
 }
 
 func (t *Table[T]) Limit2(offset, limit int64) {
 	if limit != 0 {
-		t.limit2Adapt(offset, limit)
-	}
-}
-
-func (t *Table[T]) limitAdapt(limit int64) {
-	switch t.getDB(true).GetDBType() {
-	case SQLSERVER:
-		t.limitSql = " OFFSET 0 ROWS FETCH NEXT ? ROWS ONLY "
-	case ORACLE:
-		t.limitSql = " FETCH FIRST ? ROWS ONLY "
-	case NETEZZA, GREENPLUM, POSTGRESQL, OPENGAUSS, ENTERPRISEDB, COCKROACHDB:
-		t.limitSql = " LIMIT ? OFFSET 0 "
-	case DB2, INFORMIX:
-		t.limitSql = " FETCH FIRST ? ROWS ONLY "
-	case TERADATA, FIREBIRD, SYBASE:
-		t.limitSql = ""
-	case DERBY:
-		t.limitSql = " FETCH FIRST ? ROWS ONLY "
-	case INGRES, VERTICA, MYSQL, MARIADB, SQLITE, TIDB, OCEANBASE, HSQLDB:
-		t.limitSql = " LIMIT ? "
-	default:
-		t.limitSql = ""
-	}
-	if t.limitSql != "" {
-		t.args = append(t.args, limit)
-	}
-}
-
-func (t *Table[T]) limit2Adapt(offset, limit int64) {
-	switch t.getDB(true).GetDBType() {
-	case POSTGRESQL, GREENPLUM, OPENGAUSS:
-		t.limitSql = " OFFSET ? LIMIT ? "
-		t.args = append(t.args, offset, limit)
-	case ORACLE, SQLSERVER:
-		t.limitSql = " OFFSET ? ROWS FETCH NEXT ? ROWS ONLY "
-		t.args = append(t.args, offset, limit)
-	case SQLITE, NETEZZA, INGRES, VERTICA, HSQLDB, ENTERPRISEDB, COCKROACHDB:
-		t.limitSql = " LIMIT ? OFFSET ? "
-		t.args = append(t.args, limit, offset)
-	case DB2, DERBY:
-		t.limitSql = " FETCH FIRST ? ROWS ONLY OFFSET ? ROWS "
-		t.args = append(t.args, limit, offset)
-	case SYBASE, TERADATA, FIREBIRD:
-		t.limitSql = ""
-	case MYSQL, MARIADB, TIDB, OCEANBASE:
-		t.limitSql = " LIMIT ?,? "
-		t.args = append(t.args, offset, limit)
+		t.limit, t.offset, t.limitMode = limit, offset, 2
 	}
+}
 
+// limitClause returns the limit of the query written by the Dialect of the database
+func (t *Table[T]) limitClause(g DBhandle) (LimitClause, error) {
+	switch t.limitMode {
+	case 1:
+		return GetDialect(g.GetDBType()).Limit(t.limit)
+	case 2:
+		return GetDialect(g.GetDBType()).LimitOffset(t.offset, t.limit)
+	}
+	return LimitClause{}, nil
 }
 
 func (t *Table[T]) Selects(columns ...Column[T]) (_r []*T, err error) {
@@ -375,113 +444,323 @@ func (t *Table[T]) Select(columns ...Column[T]) (_r *T, err error) {
 	return t.executeQuery(columns...)
 }
 
+// SelectsIter returns an iterator over the rows selected from the table.
+// The rows are read from the database one at a time as the loop advances instead of being
+// collected into a slice, which keeps memory usage flat for large result sets.
+// The underlying rows are closed when the loop finishes or breaks early; the iterator
+// also works inside a transaction set by UseTransaction. The query cache is not used.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.Where(hs.Id.GT(0))
+//	for h, err := range hs.SelectsIter() {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(h)
+//	}
+func (t *Table[T]) SelectsIter(columns ...Column[T]) iter.Seq2[*T, error] {
+	if columns == nil {
+		columns = t.columns
+	}
+	g := t.getDB(true)
+	if g == nil {
+		return errSeq[T](errInit)
+	}
+	sqlstr, args, err := t.selectSql(g, t.columnNames(columns))
+	if err != nil {
+		return errSeq[T](err)
+	}
+
+	if Logger.IsVaild {
+		Logger.Debug("[SELETE ITER]["+sqlstr+"]", args)
+	}
+	return scanSeq[T](g.ExecuteQueryIter(t.getContext(), sqlstr, args...))
+}
+
+// SelectsEach streams the rows selected from the table and calls f for each of them.
+// The iteration stops when f returns false.
+func (t *Table[T]) SelectsEach(f func(*T) bool, columns ...Column[T]) error {
+	return forEach(t.SelectsIter(columns...), f)
+}
+
+// SubquerySql returns the select statement of the table and its arguments, so that the
+// Table can be used as a subquery in the conditions of another query, see Field.InSubquery.
+// All the columns of the table are selected.
+func (t *Table[T]) SubquerySql() (string, []any) {
+	return t.subquerySql(t.columns)
+}
+
+// Subquery returns the query of the table selecting the given columns,
+// to be used in conditions such as Field.InSubquery or Field.GTSubquery.
+//
+// Example:
+//
+//	order := dao.NewOrders()
+//	order.Where(order.Status.EQ(1))
+//	hs := dao.NewHstest()
+//	hs.Where(hs.Id.InSubquery(order.Subquery(order.UserId)), hs.Age.GTSubquery(order.Subquery(order.Age.Avg())))
+func (t *Table[T]) Subquery(columns ...Column[T]) Subquery {
+	return &subquery[T]{t, columns}
+}
+
+func (t *Table[T]) subquerySql(columns []Column[T]) (string, []any) {
+	names := t.columnNames(columns)
+	if g := t.getDB(true); g != nil {
+		if sqlstr, args, err := t.selectSql(g, names); err == nil {
+			return sqlstr, args
+		} else if Logger.IsVaild {
+			Logger.Warn("[SUBQUERY] ", err)
+		}
+	}
+	where, _ := t.whereClause()
+	return t.commentline + " select " + strings.Join(names, ",") + " from " + t.fromSql() + where + t.groupSql + t.havingSql + t.orderSql, t.queryArgs()
+}
+
+type subquery[T any] struct {
+	table   *Table[T]
+	columns []Column[T]
+}
+
+func (s *subquery[T]) SubquerySql() (string, []any) {
+	if len(s.columns) == 0 {
+		return s.table.SubquerySql()
+	}
+	return s.table.subquerySql(s.columns)
+}
+
 func (t *Table[T]) Update() (sql.Result, error) {
+	if err := hook(t, BeforeUpdater.BeforeUpdate); err != nil {
+		return nil, err
+	}
 	modifystr := make([]string, 0)
 	args := make([]any, 0)
-	for k, v := range t.modifymap {
+	for k, v := range t.auditValues(false) {
+		if k == t.version {
+			continue
+		}
 		modifystr = append(modifystr, k+"=?")
 		args = append(args, v)
 	}
-	t.modifySql = "update " + t.tableName + " set " + strings.Join(modifystr, ",")
-	for _, v := range t.args {
-		args = append(args, v)
+	condition, conditionArgs := t.conditionSql()
+	version, checkVersion := t.value(t.version)
+	if t.version != "" {
+		modifystr = append(modifystr, t.version+"="+t.version+"+1")
+		if checkVersion {
+			condition, conditionArgs = t.versionCondition(version)
+		}
 	}
-	t.args = args
-	t.completeSql4Update()
+	sqlstr := "update " + t.tableName + " set " + strings.Join(modifystr, ",") + condition
+	args = append(args, conditionArgs...)
 
 	if Logger.IsVaild {
-		Logger.Debug("[UPDATE]["+t.sql+"]", t.args)
+		Logger.Debug("[UPDATE]["+sqlstr+"]", args)
 	}
 
-	if g := t.getDB(false); g != nil {
-		t.clearExpire()
-		return g.ExecuteUpdate(t.sql, t.args...)
-	} else {
+	g := t.getDB(false)
+	if g == nil {
 		return nil, errInit
 	}
+	t.clearExpire()
+	rs, err := g.ExecuteUpdateContext(t.getContext(), sqlstr, args...)
+	if err == nil && checkVersion {
+		if n, e := rs.RowsAffected(); e == nil && n == 0 {
+			return rs, fmt.Errorf("%w [table:%s][%s:%v]", ErrOptimisticLock, t.tableName, t.version, version)
+		}
+		t.setEntity(t.version, AsInt64(version)+1)
+	}
+	if err == nil {
+		err = hook(t, AfterUpdater.AfterUpdate)
+	}
+	return rs, err
 }
 
 func (t *Table[T]) Insert() (sql.Result, error) {
+	if err := hook(t, BeforeInserter.BeforeInsert); err != nil {
+		return nil, err
+	}
 	insertField := make([]string, 0)
 	insert_ := make([]string, 0)
 	args := make([]any, 0)
-	for k, v := range t.modifymap {
+	for k, v := range t.auditValues(true) {
 		insertField = append(insertField, k)
 		insert_ = append(insert_, "?")
 		args = append(args, v)
 	}
-	t.sql = "insert  into " + t.tableName + "(" + strings.Join(insertField, ",") + " )values(" + strings.Join(insert_, ",") + ")"
-	for _, v := range t.args {
-		args = append(args, v)
-	}
-	t.args = args
+	sqlstr := "insert  into " + t.tableName + "(" + strings.Join(insertField, ",") + " )values(" + strings.Join(insert_, ",") + ")"
 
 	if Logger.IsVaild {
-		Logger.Debug("[INSERT]["+t.sql+"]", t.args)
+		Logger.Debug("[INSERT]["+sqlstr+"]", args)
 	}
 
 	if g := t.getDB(false); g != nil {
 		t.clearExpire()
-		return g.ExecuteUpdate(t.sql, t.args...)
+		rs, err := t.insert(g, sqlstr, insertField, args)
+		if err == nil {
+			err = hook(t, AfterInserter.AfterInsert)
+		}
+		return rs, err
 	} else {
 		return nil, errInit
 	}
 }
 
 func (t *Table[T]) AddBatch() {
-	if t.batchmap == nil {
-		t.batchmap = make(map[string][]any, 0)
-	}
-	for k, v := range t.modifymap {
-		if list, b := t.batchmap[k]; b {
-			t.batchmap[k] = append(list, v)
-		} else {
-			t.batchmap[k] = []any{v}
-		}
-	}
+	t.batchrows = append(t.batchrows, maps.Clone(t.auditValues(true)))
 }
 
 func (t *Table[T]) ExecBatch() ([]sql.Result, error) {
-	if len(t.batchmap) == 0 {
+	if len(t.batchrows) == 0 {
 		return nil, nil
 	}
-	insertField := make([]string, len(t.batchmap))
-	insert_ := make([]string, len(t.batchmap))
-	i := 0
-	for k, v := range t.batchmap {
-		insertField[i] = k
+	if t.upsert != nil {
+		return t.upsertBatch()
+	}
+	insertField, batchArgs := t.batchRows()
+	insert_ := make([]string, len(insertField))
+	for i := range insert_ {
 		insert_[i] = "?"
-		if i == 0 {
-			t.batchArgs = make([][]any, len(v))
-		}
-		for j, c := range v {
-			t.batchArgs[j] = append(t.batchArgs[j], c)
-		}
-		i++
 	}
-	t.sql = " insert  into " + t.tableName + "(" + strings.Join(insertField, ",") + " )values(" + strings.Join(insert_, ",") + ")"
+	sqlstr := " insert  into " + t.tableName + "(" + strings.Join(insertField, ",") + " )values(" + strings.Join(insert_, ",") + ")"
 	if Logger.IsVaild {
-		Logger.Debug("[BATCH]["+t.sql+"]", t.batchArgs)
+		Logger.Debug("[BATCH]["+sqlstr+"]", batchArgs)
 	}
 	if g := t.getDB(false); g != nil {
 		t.clearExpire()
-		return g.ExecuteBatch(t.sql, t.batchArgs)
+		if t.generatedKey(insertField) {
+			return t.insertBatch(g, sqlstr, insertField, batchArgs)
+		}
+		return t.executeBatch(g, sqlstr, batchArgs)
 	} else {
 		return nil, errInit
 	}
 }
 
+// batchRows returns the columns set by the rows added by AddBatch and the arguments of each row,
+// nil for a column the row does not set
+func (t *Table[T]) batchRows() (columns []string, args [][]any) {
+	for _, row := range t.batchrows {
+		for _, k := range slices.Sorted(maps.Keys(row)) {
+			if !slices.Contains(columns, k) {
+				columns = append(columns, k)
+			}
+		}
+	}
+	args = make([][]any, len(t.batchrows))
+	for i, row := range t.batchrows {
+		args[i] = make([]any, len(columns))
+		for j, k := range columns {
+			args[i][j] = row[k]
+		}
+	}
+	return
+}
+
+// Upsert inserts the row of the entity, or updates the row that already holds the same
+// values of the conflict columns. The statement depends on the database type:
+// ON DUPLICATE KEY UPDATE for MySQL, MariaDB, TiDB and OceanBase, ON CONFLICT ... DO UPDATE
+// for PostgreSQL, openGauss, CockroachDB and SQLite, and MERGE for Oracle, SQL Server and DB2.
+// MySQL and its relatives resolve the conflict with the unique keys of the table, the
+// others need the conflict columns, which must have been set on the entity.
+// The MySQL statement reads the inserted values with a row alias, which requires MySQL 8.0.19.
+// The rows added by AddBatch are upserted by ExecBatch after UseUpsert.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.SetId(1)
+//	hs.SetName("donnie")
+//	hs.Upsert(hs.Id)
+func (t *Table[T]) Upsert(conflictColumns ...Column[T]) (sql.Result, error) {
+	values := t.values()
+	columns := slices.Sorted(maps.Keys(values))
+	args := make([]any, len(columns))
+	for i, k := range columns {
+		args[i] = values[k]
+	}
+	g := t.getDB(false)
+	if g == nil {
+		return nil, errInit
+	}
+	sqlstr, err := GetDialect(g.GetDBType()).Upsert(t.tableName, columns, t.conflictNames(conflictColumns))
+	if err != nil {
+		return nil, err
+	}
+
+	if Logger.IsVaild {
+		Logger.Debug("[UPSERT]["+sqlstr+"]", args)
+	}
+	t.clearExpire()
+	return g.ExecuteUpdateContext(t.getContext(), sqlstr, args...)
+}
+
+// upsertBatch executes the rows added by AddBatch as a batch of upserts on the conflict columns of UseUpsert
+func (t *Table[T]) upsertBatch() ([]sql.Result, error) {
+	columns, batchArgs := t.batchRows()
+	g := t.getDB(false)
+	if g == nil {
+		return nil, errInit
+	}
+	sqlstr, err := GetDialect(g.GetDBType()).Upsert(t.tableName, columns, t.upsert)
+	if err != nil {
+		return nil, err
+	}
+	if Logger.IsVaild {
+		Logger.Debug("[UPSERT BATCH]["+sqlstr+"]", batchArgs)
+	}
+	t.clearExpire()
+	return t.executeBatch(g, sqlstr, batchArgs)
+}
+
+func (t *Table[T]) conflictNames(columns []Column[T]) []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = unqualify(c.Name())
+	}
+	return names
+}
+
+// Delete deletes the rows of the conditions. When the table has a soft delete column,
+// see UseSoftDelete, the column of the rows is set instead and the rows are kept.
 func (t *Table[T]) Delete() (sql.Result, error) {
-	t.modifySql = " delete from " + t.tableName
-	t.completeSql4Update()
+	return t.delete(t.getSoftDelete())
+}
+
+// HardDelete deletes the rows of the conditions, including the soft deleted rows,
+// even when the table has a soft delete column.
+func (t *Table[T]) HardDelete() (sql.Result, error) {
+	return t.delete(nil)
+}
+
+// delete sets the soft delete column s of the rows of the conditions, or deletes them if s is nil,
+// and calls the delete hooks of the entity
+func (t *Table[T]) delete(s *SoftDelete) (rs sql.Result, err error) {
+	if err = hook(t, BeforeDeleter.BeforeDelete); err != nil {
+		return nil, err
+	}
+	if s != nil {
+		rs, err = t.softDeleteExec(s)
+	} else {
+		rs, err = t.hardDeleteExec()
+	}
+	if err == nil {
+		err = hook(t, AfterDeleter.AfterDelete)
+	}
+	return
+}
+
+func (t *Table[T]) hardDeleteExec() (sql.Result, error) {
+	condition, args := t.whereSql+t.groupSql+t.havingSql, append(append([]any{}, t.whereArgs...), t.havingArgs...)
+	sqlstr := " delete from " + t.tableName + condition
 
 	if Logger.IsVaild {
-		Logger.Debug("[DELETE]["+t.sql+"]", t.args)
+		Logger.Debug("[DELETE]["+sqlstr+"]", args)
 	}
 
 	if g := t.getDB(false); g != nil {
 		t.clearExpire()
-		return g.ExecuteUpdate(t.sql, t.args...)
+		return g.ExecuteUpdateContext(t.getContext(), sqlstr, args...)
 	} else {
 		return nil, errInit
 	}
@@ -491,6 +770,16 @@ func (t *Table[T]) clearExpire() {
 	gdaoCache.ClearExpireWrite[T]()
 }
 
+// hook calls the lifecycle hook of the entity of the Table if it implements H, see base.BeforeInserter
+func hook[H any, T any](t *Table[T], call func(H) error) error {
+	if t.entity != nil {
+		if h, ok := any(t.entity).(H); ok {
+			return call(h)
+		}
+	}
+	return nil
+}
+
 var serialize Serialize[map[string]any] = &Serializer{}
 
 func (t *Table[T]) Encode(m map[string]any) ([]byte, error) {
@@ -0,0 +1,130 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"github.com/donnie4w/gdao/base"
+	"iter"
+)
+
+// SetTracer sets the Tracer starting the spans of the statements, of the begin, commit and rollback
+// of the transactions, of the resolution of the mapper ids and of the cache lookups. nil removes it.
+//
+// Example:
+//
+//	recorder := &base.SpanRecorder{}
+//	gdao.SetTracer(recorder)
+func SetTracer(tracer base.Tracer) {
+	base.UseTracer(tracer)
+}
+
+// dbSystems are the db.system attributes of the database types, named as by OpenTelemetry
+var dbSystems = map[base.DBType]string{
+	MYSQL:        "mysql",
+	POSTGRESQL:   "postgresql",
+	MARIADB:      "mariadb",
+	SQLITE:       "sqlite",
+	ORACLE:       "oracle",
+	SQLSERVER:    "mssql",
+	DB2:          "db2",
+	SYBASE:       "sybase",
+	DERBY:        "derby",
+	FIREBIRD:     "firebird",
+	INGRES:       "ingres",
+	GREENPLUM:    "greenplum",
+	TERADATA:     "teradata",
+	NETEZZA:      "netezza",
+	VERTICA:      "vertica",
+	TIDB:         "tidb",
+	OCEANBASE:    "oceanbase",
+	OPENGAUSS:    "opengauss",
+	HSQLDB:       "hsqldb",
+	ENTERPRISEDB: "edb",
+	SAPHANA:      "hanadb",
+	COCKROACHDB:  "cockroachdb",
+	INFORMIX:     "informix",
+}
+
+func dbSystem(dbtype base.DBType) string {
+	if s, ok := dbSystems[dbtype]; ok {
+		return s
+	}
+	return "other_sql"
+}
+
+// traceStatement executes the statement through the interceptors in a SpanStatement span.
+// The span of StmtQueryIter ends when the iteration of the rows ends
+func traceStatement(stmt *Statement) (r *Result, err error) {
+	attrs := base.Attrs{
+		base.AttrDBSystem:    dbSystem(stmt.DBType),
+		base.AttrDBStatement: stmt.Sql,
+		base.AttrDBOperation: stmt.Kind.String(),
+		base.AttrDataSource:  stmt.DataSource,
+	}
+	var end func(error)
+	stmt.Ctx, end = base.StartSpan(stmt.Ctx, base.SpanStatement, attrs)
+	r, err = runInterceptors(stmt)
+	if r != nil {
+		switch stmt.Kind {
+		case StmtQueryIter:
+			if err == nil && r.Iter != nil {
+				r.Iter = tracedIter(attrs, end, r.Iter)
+				return
+			}
+		case StmtQueryBeans:
+			attrs[base.AttrRowsReturned] = len(r.Beans)
+		case StmtQueryBean:
+			if r.Bean != nil && r.Bean.Len() > 0 {
+				attrs[base.AttrRowsReturned] = 1
+			} else {
+				attrs[base.AttrRowsReturned] = 0
+			}
+		case StmtUpdate:
+			if r.Result != nil {
+				if n, e := r.Result.RowsAffected(); e == nil {
+					attrs[base.AttrRowsAffected] = n
+				}
+			}
+		case StmtBatch:
+			var rows int64
+			for _, rs := range r.Results {
+				if n, e := rs.RowsAffected(); e == nil {
+					rows += n
+				}
+			}
+			attrs[base.AttrRowsAffected] = rows
+		}
+	}
+	end(err)
+	return
+}
+
+// tracedIter returns the iterator ending the span of the statement when the iteration ends,
+// with the number of rows read and the first error of the iteration
+func tracedIter(attrs base.Attrs, end func(error), seq iter.Seq2[*base.DataBean, error]) iter.Seq2[*base.DataBean, error] {
+	return func(yield func(*base.DataBean, error) bool) {
+		rows := 0
+		var err error
+		defer func() {
+			attrs[base.AttrRowsReturned] = rows
+			end(err)
+		}()
+		for bean, e := range seq {
+			if e != nil {
+				if err == nil {
+					err = e
+				}
+			} else {
+				rows++
+			}
+			if !yield(bean, e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"database/sql/driver"
+	"errors"
+	"github.com/donnie4w/gdao/base"
+	"testing"
+	"time"
+)
+
+func Test_traceQueryIter(t *testing.T) {
+	d := useTestDB(t, MYSQL, []string{"id"}, []driver.Value{int64(1)}, []driver.Value{int64(2)})
+	recorder := &base.SpanRecorder{}
+	SetTracer(recorder)
+	t.Cleanup(func() { SetTracer(nil) })
+
+	hs := newHstest()
+	seq := hs.SelectsIter()
+	if s := recorder.Spans(); len(s) != 0 {
+		t.Fatal("the span ends before the iteration", s)
+	}
+	start := time.Now()
+	for _, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	s := recorder.Spans()
+	if len(s) != 1 || s[0].Name != base.SpanStatement || s[0].Err != nil || s[0].Attrs[base.AttrRowsReturned] != 2 {
+		t.Fatal(s)
+	}
+	if s[0].End.Before(start) || s[0].End.Sub(s[0].Start) < 2*time.Millisecond {
+		t.Fatal("the span does not cover the iteration", s[0].Start, s[0].End)
+	}
+
+	recorder.Reset()
+	d.queryErr = errors.New("query failed")
+	for range hs.SelectsIter() {
+	}
+	if s := recorder.Spans(); len(s) != 1 || s[0].Err != d.queryErr || s[0].Attrs[base.AttrRowsReturned] != 0 {
+		t.Fatal(s)
+	}
+}
@@ -8,8 +8,10 @@
 package gdao
 
 import (
+	"context"
 	"database/sql"
 	. "github.com/donnie4w/gdao/base"
+	"iter"
 )
 
 type tx struct {
@@ -17,27 +19,51 @@ type tx struct {
 	dbtype  DBType
 	gdbc    gdbcHandle
 	isclose bool
+	ctx     context.Context
 }
 
-func newTX(db DBhandle) (x *tx, err error) {
-	x = new(tx)
-	if x.tx, err = db.GetDB().Begin(); err == nil {
+func newTX(ctx context.Context, db DBhandle, opts *sql.TxOptions) (x *tx, err error) {
+	x = &tx{ctx: ctx}
+	_, end := StartSpan(ctx, SpanTxBegin, txAttrs(db.GetDB(), db.GetDBType()))
+	if x.tx, err = db.GetDB().BeginTx(ctx, opts); err == nil {
 		x.dbtype = db.GetDBType()
 		x.gdbc = newGdbcHandle(x.tx, db.GetDB(), db.GetDBType())
+		AddGauge(MetricOpenTransactions, 1, LabelDataSource, dataSourceName(db.GetDB()))
 	}
+	end(err)
 	return x, err
 }
 
+func txAttrs(db *sql.DB, dbtype DBType) Attrs {
+	return Attrs{AttrDBSystem: dbSystem(dbtype), AttrDataSource: dataSourceName(db)}
+}
+
 func (x *tx) IsClose() bool {
 	return x.isclose
 }
 
 func (x *tx) Commit() (err error) {
-	return x.tx.Commit()
+	_, end := StartSpan(x.ctx, SpanTxCommit, txAttrs(x.GetDB(), x.dbtype))
+	err = x.tx.Commit()
+	x.close()
+	end(err)
+	return
 }
 
 func (x *tx) Rollback() (err error) {
-	return x.tx.Rollback()
+	_, end := StartSpan(x.ctx, SpanTxRollback, txAttrs(x.GetDB(), x.dbtype))
+	err = x.tx.Rollback()
+	x.close()
+	end(err)
+	return
+}
+
+// close marks the transaction as committed or rolled back
+func (x *tx) close() {
+	if !x.isclose {
+		x.isclose = true
+		AddGauge(MetricOpenTransactions, -1, LabelDataSource, dataSourceName(x.GetDB()))
+	}
 }
 
 func (x *tx) Close() (err error) {
@@ -52,30 +78,65 @@ func (x *tx) GetTransaction() (Transaction, error) {
 	return x, nil
 }
 
+func (x *tx) GetTransactionContext(context.Context, *sql.TxOptions) (Transaction, error) {
+	return x, nil
+}
+
 func (x *tx) GetDB() *sql.DB {
 	return x.gdbc.GetDB()
 }
 
 func (x *tx) ExecuteUpdate(sqlstr string, args ...any) (sql.Result, error) {
-	return x.gdbc.ExecuteUpdate(sqlstr, args...)
+	return x.gdbc.ExecuteUpdateContext(x.ctx, sqlstr, args...)
 }
 
 func (x *tx) ExecuteBatch(sqlstr string, args [][]any) (r []sql.Result, err error) {
-	return x.gdbc.ExecuteBatch(sqlstr, args)
+	return x.gdbc.ExecuteBatchContext(x.ctx, sqlstr, args)
 }
 
 func (x *tx) ExecuteQueryBean(sqlstr string, args ...any) *DataBean {
-	return x.gdbc.ExecuteQueryBean(sqlstr, args...)
+	return x.gdbc.ExecuteQueryBeanContext(x.ctx, sqlstr, args...)
 }
 
 func (x *tx) ExecuteQueryBeans(sqlstr string, args ...any) (r *DataBeans) {
-	return x.gdbc.ExecuteQueryBeans(sqlstr, args...)
+	return x.gdbc.ExecuteQueryBeansContext(x.ctx, sqlstr, args...)
+}
+
+func (x *tx) ExecuteUpdateContext(ctx context.Context, sqlstr string, args ...any) (sql.Result, error) {
+	return x.gdbc.ExecuteUpdateContext(ctx, sqlstr, args...)
+}
+
+func (x *tx) ExecuteBatchContext(ctx context.Context, sqlstr string, args [][]any) (r []sql.Result, err error) {
+	return x.gdbc.ExecuteBatchContext(ctx, sqlstr, args)
+}
+
+func (x *tx) ExecuteQueryBeanContext(ctx context.Context, sqlstr string, args ...any) *DataBean {
+	return x.gdbc.ExecuteQueryBeanContext(ctx, sqlstr, args...)
+}
+
+func (x *tx) ExecuteQueryBeansContext(ctx context.Context, sqlstr string, args ...any) (r *DataBeans) {
+	return x.gdbc.ExecuteQueryBeansContext(ctx, sqlstr, args...)
+}
+
+func (x *tx) ExecuteQueryIter(ctx context.Context, sqlstr string, args ...any) iter.Seq2[*DataBean, error] {
+	return x.gdbc.ExecuteQueryIter(ctx, sqlstr, args...)
 }
 
 func NewTransaction() (r Transaction, err error) {
-	return newTX(defaultDBhandle)
+	return newTX(context.Background(), defaultDBhandle, nil)
 }
 
 func NewTransactionWithDBhandle(db DBhandle) (r Transaction, err error) {
-	return newTX(db)
+	return newTX(context.Background(), db, nil)
+}
+
+// NewTransactionContext begins a transaction on db bound to ctx.
+// The provided context is used until the transaction is committed or rolled back;
+// if the context is canceled, the transaction is rolled back by database/sql.
+// If db is nil, the default DBhandle is used.
+func NewTransactionContext(ctx context.Context, db DBhandle, opts *sql.TxOptions) (r Transaction, err error) {
+	if db == nil {
+		db = defaultDBhandle
+	}
+	return newTX(ctx, db, opts)
 }
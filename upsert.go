@@ -0,0 +1,114 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"fmt"
+	"strings"
+)
+
+// upsertColumns checks the columns of an upsert and returns the columns to update on conflict
+func upsertColumns(table string, columns, conflicts []string) ([]string, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("upsert %s: no column value was set", table)
+	}
+	for _, c := range conflicts {
+		if !containsColumn(columns, c) {
+			return nil, fmt.Errorf("upsert %s: the conflict column %s has no value", table, c)
+		}
+	}
+	updates := make([]string, 0, len(columns))
+	for _, c := range columns {
+		if !containsColumn(conflicts, c) {
+			updates = append(updates, c)
+		}
+	}
+	return updates, nil
+}
+
+// duplicateKeyUpsert : insert ... on duplicate key update, the updated values are read from the row alias,
+// or with the values() function when alias is empty
+func duplicateKeyUpsert(table string, columns, conflicts []string, alias string) (string, error) {
+	updates, err := upsertColumns(table, columns, conflicts)
+	if err != nil {
+		return "", err
+	}
+	if len(updates) == 0 {
+		updates = columns[:1]
+	}
+	s := "insert into " + table + "(" + strings.Join(columns, ",") + ")values(" + marks(len(columns)) + ")"
+	ss := make([]string, len(updates))
+	for i, c := range updates {
+		if alias != "" {
+			ss[i] = c + "=" + alias + "." + c
+		} else {
+			ss[i] = c + "=values(" + c + ")"
+		}
+	}
+	if alias != "" {
+		s = s + " as " + alias
+	}
+	return s + " on duplicate key update " + strings.Join(ss, ","), nil
+}
+
+// onConflictUpsert : insert ... on conflict (...) do update
+func onConflictUpsert(table string, columns, conflicts []string) (string, error) {
+	updates, err := upsertColumns(table, columns, conflicts)
+	if err != nil {
+		return "", err
+	}
+	if len(conflicts) == 0 {
+		return "", fmt.Errorf("upsert %s: conflict columns are required", table)
+	}
+	s := "insert into " + table + "(" + strings.Join(columns, ",") + ")values(" + marks(len(columns)) + ") on conflict (" + strings.Join(conflicts, ",") + ")"
+	if len(updates) == 0 {
+		return s + " do nothing", nil
+	}
+	ss := make([]string, len(updates))
+	for i, c := range updates {
+		ss[i] = c + "=excluded." + c
+	}
+	return s + " do update set " + strings.Join(ss, ","), nil
+}
+
+// mergeUpsert : merge into ... using, using is the source of the row with the alias s
+func mergeUpsert(table string, columns, conflicts []string, using, end string) (string, error) {
+	updates, err := upsertColumns(table, columns, conflicts)
+	if err != nil {
+		return "", err
+	}
+	if len(conflicts) == 0 {
+		return "", fmt.Errorf("upsert %s: conflict columns are required", table)
+	}
+	on := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		on[i] = "d." + c + "=s." + c
+	}
+	values := make([]string, len(columns))
+	for i, c := range columns {
+		values[i] = "s." + c
+	}
+	s := "merge into " + table + " d using " + using + " on (" + strings.Join(on, " and ") + ")"
+	if len(updates) > 0 {
+		ss := make([]string, len(updates))
+		for i, c := range updates {
+			ss[i] = "d." + c + "=s." + c
+		}
+		s = s + " when matched then update set " + strings.Join(ss, ",")
+	}
+	return s + " when not matched then insert (" + strings.Join(columns, ",") + ") values (" + strings.Join(values, ",") + ")" + end, nil
+}
+
+func containsColumn(columns []string, column string) bool {
+	for _, c := range columns {
+		if strings.EqualFold(c, column) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	. "github.com/donnie4w/gdao/base"
+	"strings"
+	"testing"
+)
+
+func Test_dialectUpsert(t *testing.T) {
+	tests := []struct {
+		dbtype    DBType
+		columns   []string
+		conflicts []string
+		sql       string
+	}{
+		{MYSQL, []string{"id", "name", "age"}, []string{"id"},
+			"insert into hstest(id,name,age)values(?,?,?) as new on duplicate key update name=new.name,age=new.age"},
+		{TIDB, []string{"id", "name"}, []string{"id"},
+			"insert into hstest(id,name)values(?,?) on duplicate key update name=values(name)"},
+		{MARIADB, []string{"id"}, nil,
+			"insert into hstest(id)values(?) on duplicate key update id=values(id)"},
+		{POSTGRESQL, []string{"id", "name", "age"}, []string{"id"},
+			"insert into hstest(id,name,age)values(?,?,?) on conflict (id) do update set name=excluded.name,age=excluded.age"},
+		{SQLITE, []string{"id"}, []string{"id"},
+			"insert into hstest(id)values(?) on conflict (id) do nothing"},
+		{ORACLE, []string{"id", "name", "age"}, []string{"id"},
+			"merge into hstest d using (select ? id,? name,? age from dual) s on (d.id=s.id) when matched then update set d.name=s.name,d.age=s.age when not matched then insert (id,name,age) values (s.id,s.name,s.age)"},
+		{ORACLE, []string{"id"}, []string{"id"},
+			"merge into hstest d using (select ? id from dual) s on (d.id=s.id) when not matched then insert (id) values (s.id)"},
+		{SQLSERVER, []string{"id", "name", "age"}, []string{"id", "name"},
+			"merge into hstest d using (select ? as id,? as name,? as age) s on (d.id=s.id and d.name=s.name) when matched then update set d.age=s.age when not matched then insert (id,name,age) values (s.id,s.name,s.age);"},
+		{DB2, []string{"id", "name", "age"}, []string{"id"},
+			"merge into hstest d using (values (?,?,?)) s(id,name,age) on (d.id=s.id) when matched then update set d.name=s.name,d.age=s.age when not matched then insert (id,name,age) values (s.id,s.name,s.age)"},
+		{SAPHANA, []string{"id", "name"}, []string{"id"},
+			"upsert hstest(id,name)values(?,?) with primary key"},
+		{FIREBIRD, []string{"id", "name"}, []string{"id"},
+			"update or insert into hstest(id,name)values(?,?) matching (id)"},
+	}
+	for _, tt := range tests {
+		s, err := GetDialect(tt.dbtype).Upsert("hstest", tt.columns, tt.conflicts)
+		if err != nil {
+			t.Fatal(tt.dbtype, err)
+		}
+		checkSql(t, s, nil, tt.sql)
+	}
+}
+
+func Test_dialectUpsertError(t *testing.T) {
+	tests := []struct {
+		dbtype    DBType
+		columns   []string
+		conflicts []string
+		err       string
+	}{
+		{DERBY, []string{"id"}, []string{"id"}, "upsert is not supported by the derby dialect"},
+		{SYBASE, []string{"id"}, []string{"id"}, "upsert is not supported by the sybase dialect"},
+		{HSQLDB, []string{"id"}, []string{"id"}, "upsert is not supported by the hsqldb dialect"},
+		{POSTGRESQL, []string{"id"}, nil, "upsert hstest: conflict columns are required"},
+		{SQLSERVER, []string{"id"}, nil, "upsert hstest: conflict columns are required"},
+		{MYSQL, []string{"name"}, []string{"id"}, "upsert hstest: the conflict column id has no value"},
+		{ORACLE, nil, nil, "upsert hstest: no column value was set"},
+	}
+	for _, tt := range tests {
+		if _, err := GetDialect(tt.dbtype).Upsert("hstest", tt.columns, tt.conflicts); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Fatal(tt.dbtype, err)
+		}
+	}
+}
+
+func Test_Upsert(t *testing.T) {
+	tests := []struct {
+		dbtype DBType
+		sql    string
+	}{
+		{MYSQL, "insert into hstest(age,id,name)values(?,?,?) as new on duplicate key update age=new.age,name=new.name[18 1 donnie]"},
+		{POSTGRESQL, "insert into hstest(age,id,name)values($1,$2,$3) on conflict (id) do update set age=excluded.age,name=excluded.name[18 1 donnie]"},
+		{ORACLE, "merge into hstest d using (select :v1 age,:v2 id,:v3 name from dual) s on (d.id=s.id) when matched then update set d.age=s.age,d.name=s.name when not matched then insert (age,id,name) values (s.age,s.id,s.name)[18 1 donnie]"},
+		{SQLSERVER, "merge into hstest d using (select @p1 as age,@p2 as id,@p3 as name) s on (d.id=s.id) when matched then update set d.age=s.age,d.name=s.name when not matched then insert (age,id,name) values (s.age,s.id,s.name);[18 1 donnie]"},
+	}
+	for _, tt := range tests {
+		d := useTestDB(t, tt.dbtype, nil)
+		hs := newHstest()
+		hs.SetId(1).SetName("donnie").SetAge(18)
+		if _, err := hs.Upsert(hs.ID); err != nil {
+			t.Fatal(tt.dbtype, err)
+		}
+		if s := d.statements(); len(s) != 1 || s[0].String() != tt.sql {
+			t.Fatalf("%v:\n got: %v\nwant: %s", tt.dbtype, s, tt.sql)
+		}
+	}
+}
+
+func Test_UpsertBatch(t *testing.T) {
+	d := useTestDB(t, POSTGRESQL, nil)
+	hs := newHstest()
+	hs.UseUpsert(hs.ID)
+	hs.SetId(1).SetName("a")
+	hs.AddBatch()
+	hs.SetId(2).SetName("b")
+	hs.AddBatch()
+	if rs, err := hs.ExecBatch(); err != nil || len(rs) != 2 {
+		t.Fatal(rs, err)
+	}
+	checkStatements(t, d, "insert into hstest(id,name)values($1,$2) on conflict (id) do update set name=excluded.name[1 a]",
+		"insert into hstest(id,name)values($1,$2) on conflict (id) do update set name=excluded.name[2 b]")
+
+	d = useTestDB(t, MYSQL, nil)
+	hs.UseMultiRowBatch(true)
+	if rs, err := hs.ExecBatch(); err != nil || len(rs) != 1 {
+		t.Fatal(rs, err)
+	}
+	checkStatements(t, d, "insert into hstest(id,name)values(?,?),(?,?) as new on duplicate key update name=new.name[1 a 2 b]")
+}
@@ -8,10 +8,11 @@
 package gdao
 
 import (
-	"database/sql"
 	"fmt"
 	"github.com/donnie4w/gdao/base"
-	"strconv"
+	"github.com/donnie4w/gdao/util"
+	"strings"
+	"sync"
 )
 
 var errInit = fmt.Errorf("the gdao DataSource was not initialized(Hint: gdao.Init(db, dbtype))")
@@ -55,43 +56,66 @@ func PreCompile(limit uint32) {
 	stmtLimit = int64(limit)
 }
 
+// parseSql rewrites the ? bind markers of sqlstr into the placeholders of the dialect of dbtype,
+// see util.RewritePlaceholders, and binds args with the dialect. The rewritten statements are cached.
 func parseSql(dbtype base.DBType, sqlstr string, args ...any) string {
+	d := GetDialect(dbtype)
+	rewrite := len(args) > 0 && d.Placeholder(1) != "?"
+	if !rewrite && !strings.Contains(sqlstr, "??") {
+		return sqlstr
+	}
+	sqlstr = rewriteSql(dbtype, d, sqlstr, rewrite)
 	if len(args) > 0 {
-		switch dbtype {
-		case POSTGRESQL, GREENPLUM, OPENGAUSS:
-			s := ""
-			k := 1
-			for _, c := range sqlstr {
-				if c == '?' {
-					s = s + "$" + strconv.Itoa(k)
-					k++
-				} else {
-					s = s + string(c)
-				}
-			}
-			return s
-		case ORACLE:
-			s := ""
-			k := 1
-			for _, c := range sqlstr {
-				if c == '?' {
-					s = s + ":v" + strconv.Itoa(k)
-					k++
-				} else {
-					s = s + string(c)
-				}
-			}
-			for i, arg := range args {
-				if vs, ok := arg.([]any); ok {
-					for j, v := range vs {
-						vs[j] = sql.Named("v"+strconv.Itoa(j+1), v)
-					}
-				} else {
-					args[i] = sql.Named("v"+strconv.Itoa(i+1), arg)
+		for i, arg := range args {
+			if vs, ok := arg.([]any); ok {
+				for j, v := range vs {
+					vs[j] = d.Bind(j+1, v)
 				}
+			} else {
+				args[i] = d.Bind(i+1, arg)
 			}
-			return s
 		}
 	}
 	return sqlstr
 }
+
+type sqlKey struct {
+	dbtype  base.DBType
+	rewrite bool
+	sql     string
+}
+
+const sqlCacheLimit = 1 << 12
+
+var sqlCache = struct {
+	sync.RWMutex
+	m map[sqlKey]string
+}{m: make(map[sqlKey]string)}
+
+func rewriteSql(dbtype base.DBType, d Dialect, sqlstr string, rewrite bool) string {
+	key := sqlKey{dbtype, rewrite, sqlstr}
+	sqlCache.RLock()
+	s, ok := sqlCache.m[key]
+	sqlCache.RUnlock()
+	if ok {
+		return s
+	}
+	var placeholder func(int) string
+	if rewrite {
+		placeholder = d.Placeholder
+	}
+	s, _ = util.RewritePlaceholders(sqlstr, placeholder)
+	sqlCache.Lock()
+	if len(sqlCache.m) >= sqlCacheLimit {
+		sqlCache.m = make(map[sqlKey]string)
+	}
+	sqlCache.m[key] = s
+	sqlCache.Unlock()
+	return s
+}
+
+func clearSqlCache() {
+	sqlCache.Lock()
+	sqlCache.m = make(map[sqlKey]string)
+	sqlCache.Unlock()
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	fingerprintList = regexp.MustCompile(`\(\?(?:,\?)*\)`)
+	fingerprintRows = regexp.MustCompile(`\(\?\)(?:,\(\?\))+`)
+)
+
+// Fingerprint returns the normalized form of sqlstr shared by the statements that only differ by their values.
+// The string and number literals are replaced by ?, the lists of values in parentheses, such as the IN lists
+// and the rows of a multi-row insert, are collapsed into (?), the comments are removed, the white spaces
+// are collapsed and the words are lowercased. The quoted identifiers are kept.
+func Fingerprint(sqlstr string) string {
+	var b strings.Builder
+	b.Grow(len(sqlstr))
+	space := false
+	write := func(s string) {
+		if space && b.Len() > 0 {
+			last := b.String()[b.Len()-1]
+			if last != '(' && last != ',' && s[0] != ')' && s[0] != ',' {
+				b.WriteByte(' ')
+			}
+		}
+		space = false
+		b.WriteString(s)
+	}
+	for i := 0; i < len(sqlstr); {
+		c := sqlstr[i]
+		switch {
+		case c == '\'':
+			i = skipQuoted(sqlstr, i, c)
+			write("?")
+			continue
+		case c == '"' || c == '`':
+			j := skipQuoted(sqlstr, i, c)
+			write(sqlstr[i:j])
+			i = j
+			continue
+		case c == '-' && i+1 < len(sqlstr) && sqlstr[i+1] == '-':
+			if j := strings.IndexByte(sqlstr[i:], '\n'); j >= 0 {
+				i = i + j + 1
+			} else {
+				i = len(sqlstr)
+			}
+			space = true
+			continue
+		case c == '/' && i+1 < len(sqlstr) && sqlstr[i+1] == '*':
+			if j := strings.Index(sqlstr[i+2:], "*/"); j >= 0 {
+				i = i + j + 4
+			} else {
+				i = len(sqlstr)
+			}
+			space = true
+			continue
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			space = true
+			i++
+			continue
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(sqlstr) && (isWordByte(sqlstr[j]) || sqlstr[j] == '.') {
+				j++
+			}
+			write("?")
+			i = j
+			continue
+		case isWordByte(c):
+			j := i
+			for j < len(sqlstr) && isWordByte(sqlstr[j]) {
+				j++
+			}
+			write(strings.ToLower(sqlstr[i:j]))
+			i = j
+			continue
+		}
+		write(sqlstr[i : i+1])
+		i++
+	}
+	s := fingerprintList.ReplaceAllString(b.String(), "(?)")
+	return fingerprintRows.ReplaceAllString(s, "(?)")
+}
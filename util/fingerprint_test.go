@@ -0,0 +1,26 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package util
+
+import "testing"
+
+func Test_fingerprint(t *testing.T) {
+	tests := [][2]string{
+		{"SELECT * FROM t WHERE id = 10 AND name='it''s'", "select * from t where id = ? and name=?"},
+		{"select * from t where id in (1, 2, 3) and age>?", "select * from t where id in (?) and age>?"},
+		{"select * from t where id IN ( ?,?,? )", "select * from t where id in (?)"},
+		{"insert into t(a,b) values (?,?), (?, ?),(1,'x')", "insert into t(a,b) values (?)"},
+		{"select \"Name\", c1 from t -- comment\n where x = 1.5 /* c */ limit 10", "select \"Name\",c1 from t where x = ? limit ?"},
+		{"select count(*) from t", "select count(*) from t"},
+	}
+	for _, test := range tests {
+		if s := Fingerprint(test[0]); s != test[1] {
+			t.Errorf("got %s, want %s", s, test[1])
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package util
+
+import (
+	"strings"
+)
+
+// RewritePlaceholders replaces the ? bind markers of sqlstr with placeholder(n), n counting from 1,
+// and returns the new statement and the number of bind markers.
+// A ? inside a string literal, a quoted identifier, a dollar-quoted string or a comment is not
+// a bind marker, and neither are the PostgreSQL JSONB operators ?| and ?&. A ?? is an escaped
+// literal ? and is written as a single ?.
+// A nil placeholder keeps the bind markers and only unescapes ??.
+func RewritePlaceholders(sqlstr string, placeholder func(n int) string) (string, int) {
+	builder := strings.Builder{}
+	builder.Grow(len(sqlstr) + 16)
+	n := 0
+	for i := 0; i < len(sqlstr); {
+		c := sqlstr[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			j := skipQuoted(sqlstr, i, c)
+			builder.WriteString(sqlstr[i:j])
+			i = j
+		case c == '-' && i+1 < len(sqlstr) && sqlstr[i+1] == '-':
+			j := strings.IndexByte(sqlstr[i:], '\n')
+			if j < 0 {
+				j = len(sqlstr) - i - 1
+			}
+			builder.WriteString(sqlstr[i : i+j+1])
+			i = i + j + 1
+		case c == '/' && i+1 < len(sqlstr) && sqlstr[i+1] == '*':
+			j := strings.Index(sqlstr[i+2:], "*/")
+			if j < 0 {
+				j = len(sqlstr) - i - 4
+			}
+			builder.WriteString(sqlstr[i : i+j+4])
+			i = i + j + 4
+		case c == '$' && (i == 0 || !isWordByte(sqlstr[i-1])):
+			j := skipDollarQuoted(sqlstr, i)
+			builder.WriteString(sqlstr[i:j])
+			i = j
+		case c == '?':
+			next := byte(0)
+			if i+1 < len(sqlstr) {
+				next = sqlstr[i+1]
+			}
+			switch {
+			case next == '?':
+				builder.WriteByte('?')
+				i += 2
+			case next == '&' || next == '|' && (i+2 >= len(sqlstr) || sqlstr[i+2] != '|'):
+				builder.WriteString(sqlstr[i : i+2])
+				i += 2
+			default:
+				n++
+				if placeholder != nil {
+					builder.WriteString(placeholder(n))
+				} else {
+					builder.WriteByte('?')
+				}
+				i++
+			}
+		default:
+			builder.WriteByte(c)
+			i++
+		}
+	}
+	return builder.String(), n
+}
+
+// skipQuoted returns the index following the quoted text starting at i, a doubled quote is an escaped quote
+func skipQuoted(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		if s[j] == quote {
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// skipDollarQuoted returns the index following the PostgreSQL dollar-quoted string $tag$...$tag$
+// starting at i, or i+1 if there is none, such as a $1 placeholder
+func skipDollarQuoted(s string, i int) int {
+	j := i + 1
+	for j < len(s) && isWordByte(s[j]) && (j > i+1 || s[j] < '0' || s[j] > '9') {
+		j++
+	}
+	if j >= len(s) || s[j] != '$' {
+		return i + 1
+	}
+	tag := s[i : j+1]
+	if k := strings.Index(s[j+1:], tag); k >= 0 {
+		return j + 1 + k + len(tag)
+	}
+	return len(s)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// topLevelWords calls f with the index of every word of sqlstr outside parentheses,
+// string literals, quoted identifiers and comments, until f returns false
+func topLevelWords(sqlstr string, f func(i int) bool) {
+	depth := 0
+	for i := 0; i < len(sqlstr); {
+		c := sqlstr[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(sqlstr, i, c)
+			continue
+		case c == '-' && i+1 < len(sqlstr) && sqlstr[i+1] == '-':
+			if j := strings.IndexByte(sqlstr[i:], '\n'); j >= 0 {
+				i = i + j + 1
+			} else {
+				i = len(sqlstr)
+			}
+			continue
+		case c == '/' && i+1 < len(sqlstr) && sqlstr[i+1] == '*':
+			if j := strings.Index(sqlstr[i+2:], "*/"); j >= 0 {
+				i = i + j + 4
+			} else {
+				i = len(sqlstr)
+			}
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && isWordByte(c) && (i == 0 || !isWordByte(sqlstr[i-1])):
+			if !f(i) {
+				return
+			}
+		}
+		i++
+	}
+}
+
+// hasKeywords reports whether s starts with the keywords separated by white spaces, case-insensitively
+func hasKeywords(s string, keywords ...string) bool {
+	for n, k := range keywords {
+		if n > 0 {
+			if t := strings.TrimLeft(s, " \t\r\n"); len(t) < len(s) {
+				s = t
+			} else {
+				return false
+			}
+		}
+		if len(s) < len(k) || !strings.EqualFold(s[:len(k)], k) || len(s) > len(k) && isWordByte(s[len(k)]) {
+			return false
+		}
+		s = s[len(k):]
+	}
+	return true
+}
+
+// TrimOrderBy removes the order by clause of the outermost select of sqlstr, with everything
+// following it such as a limit. An order by inside parentheses, a string literal or a comment is kept.
+func TrimOrderBy(sqlstr string) string {
+	at := -1
+	topLevelWords(sqlstr, func(i int) bool {
+		if hasKeywords(sqlstr[i:], "order", "by") {
+			at = i
+		}
+		return true
+	})
+	if at < 0 {
+		return sqlstr
+	}
+	return strings.TrimRight(sqlstr[:at], " \t\r\n")
+}
+
+// whereEnds are the clauses that can follow the where clause of a statement
+var whereEnds = [][]string{{"group", "by"}, {"having"}, {"window"}, {"order", "by"}, {"limit"}, {"offset"}, {"fetch"},
+	{"for"}, {"union"}, {"intersect"}, {"except"}, {"minus"}, {"returning"}}
+
+// AddCondition adds the condition to the where clause of the outermost statement of sqlstr with and,
+// or adds a where clause with the condition if there is none. The where clause of a statement
+// in parentheses is kept, and so is the one of a statement following a union.
+func AddCondition(sqlstr, condition string) string {
+	where, end := -1, len(sqlstr)
+	topLevelWords(sqlstr, func(i int) bool {
+		if where < 0 && hasKeywords(sqlstr[i:], "where") {
+			where = i
+			return true
+		}
+		for _, keywords := range whereEnds {
+			if hasKeywords(sqlstr[i:], keywords...) {
+				end = i
+				return false
+			}
+		}
+		return true
+	})
+	head, tail := strings.TrimRight(sqlstr[:end], " \t\r\n"), sqlstr[end:]
+	if tail != "" {
+		tail = " " + tail
+	}
+	if where < 0 {
+		return head + " where " + condition + tail
+	}
+	return head[:where+5] + " (" + strings.TrimSpace(head[where+5:]) + ") and " + condition + tail
+}
+
+// DeleteToUpdate rewrites the statement delete from table where ... into update table set ... where ...,
+// and reports whether sqlstr is such a delete statement.
+func DeleteToUpdate(sqlstr, set string) (string, bool) {
+	s := strings.TrimSpace(sqlstr)
+	if !hasKeywords(s, "delete") {
+		return sqlstr, false
+	}
+	s = strings.TrimLeft(s[6:], " \t\r\n")
+	if hasKeywords(s, "from") {
+		s = strings.TrimLeft(s[4:], " \t\r\n")
+	}
+	end := len(s)
+	topLevelWords(s, func(i int) bool {
+		if hasKeywords(s[i:], "where") {
+			end = i
+			return false
+		}
+		return true
+	})
+	table, where := strings.TrimSpace(s[:end]), s[end:]
+	if table == "" {
+		return sqlstr, false
+	}
+	if where != "" {
+		where = " " + where
+	}
+	return "update " + table + " set " + set + where, true
+}
+
+// StatementTable returns the table of the outermost statement of sqlstr: the first table following from
+// in a select or a delete, into in an insert, or update in an update. The quotes of the name are removed.
+// It returns "" if there is none, such as for a select of a derived table.
+func StatementTable(sqlstr string) (table string) {
+	topLevelWords(sqlstr, func(i int) bool {
+		for _, keyword := range []string{"from", "into", "update"} {
+			if hasKeywords(sqlstr[i:], keyword) {
+				table = leadingName(sqlstr[i+len(keyword):])
+				return table == ""
+			}
+		}
+		return true
+	})
+	return
+}
+
+// leadingName returns the name, qualified or not, at the start of s, without its quotes
+func leadingName(s string) string {
+	s = strings.TrimLeft(s, " \t\r\n")
+	j := 0
+	for j < len(s) {
+		if c := s[j]; c == '"' || c == '`' {
+			j = skipQuoted(s, j, c)
+		} else if c == '[' {
+			if k := strings.IndexByte(s[j:], ']'); k >= 0 {
+				j += k + 1
+			} else {
+				j = len(s)
+			}
+		} else if isWordByte(c) || c == '.' {
+			j++
+		} else {
+			break
+		}
+	}
+	return nameQuotes.Replace(s[:j])
+}
+
+var nameQuotes = strings.NewReplacer(`"`, "", "`", "", "[", "", "]", "")
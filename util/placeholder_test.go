@@ -0,0 +1,100 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func Test_rewritePlaceholders(t *testing.T) {
+	dollar := func(n int) string { return "$" + strconv.Itoa(n) }
+	tests := [][2]string{
+		{"select * from t where id=? and name=?", "select * from t where id=$1 and name=$2"},
+		{"select * from t where note like '%?%' and id=?", "select * from t where note like '%?%' and id=$1"},
+		{"select 'it''s ?', \"a?b\" from t where id=?", "select 'it''s ?', \"a?b\" from t where id=$1"},
+		{"select * from t -- why?\nwhere id=? /* ? */", "select * from t -- why?\nwhere id=$1 /* ? */"},
+		{"select * from t where data ?| array['a'] and data ?& array['b'] and id=?", "select * from t where data ?| array['a'] and data ?& array['b'] and id=$1"},
+		{"select ?||'x' from t", "select $1||'x' from t"},
+		{"select * from t where data ?? 'key' and id=?", "select * from t where data ? 'key' and id=$1"},
+		{"select $tag$ ? $tag$, v$session from t where id=?", "select $tag$ ? $tag$, v$session from t where id=$1"},
+	}
+	for _, test := range tests {
+		s, n := RewritePlaceholders(test[0], dollar)
+		fmt.Println(s, n)
+		if s != test[1] {
+			t.Errorf("got %s, want %s", s, test[1])
+		}
+	}
+	if s, n := RewritePlaceholders("insert into t values(?, '?')", nil); s != "insert into t values(?, '?')" || n != 1 {
+		t.Error(s, n)
+	}
+}
+
+func Test_trimOrderBy(t *testing.T) {
+	tests := [][2]string{
+		{"select * from t where id>? order by id desc limit 10", "select * from t where id>?"},
+		{"select * from t where name='order by' Order\n By id", "select * from t where name='order by'"},
+		{"select * from (select * from t order by id) s where id in (select id from u order by id)", "select * from (select * from t order by id) s where id in (select id from u order by id)"},
+		{"select orderby, border from t -- order by id", "select orderby, border from t -- order by id"},
+		{"select id, count(*) from t group by id having count(*)>? order by 2", "select id, count(*) from t group by id having count(*)>?"},
+	}
+	for _, test := range tests {
+		if s := TrimOrderBy(test[0]); s != test[1] {
+			t.Errorf("got %s, want %s", s, test[1])
+		}
+	}
+}
+
+func Test_addCondition(t *testing.T) {
+	tests := [][2]string{
+		{"select * from t", "select * from t where deleted_at is null"},
+		{"select * from t where id>? or name=?", "select * from t where (id>? or name=?) and deleted_at is null"},
+		{"select * from t where id in (select id from u where x=1) order by id limit 10", "select * from t where (id in (select id from u where x=1)) and deleted_at is null order by id limit 10"},
+		{"select id, count(*) from t group by id", "select id, count(*) from t where deleted_at is null group by id"},
+		{"select * from t WHERE name='group by' Order By id", "select * from t WHERE (name='group by') and deleted_at is null Order By id"},
+	}
+	for _, test := range tests {
+		if s := AddCondition(test[0], "deleted_at is null"); s != test[1] {
+			t.Errorf("got %s, want %s", s, test[1])
+		}
+	}
+}
+
+func Test_deleteToUpdate(t *testing.T) {
+	tests := [][2]string{
+		{"delete from t where id=?", "update t set deleted_at=current_timestamp where id=?"},
+		{"DELETE t a WHERE a.id in (select id from u where x=?)", "update t a set deleted_at=current_timestamp WHERE a.id in (select id from u where x=?)"},
+		{"delete from t", "update t set deleted_at=current_timestamp"},
+		{"select * from t", "select * from t"},
+	}
+	for _, test := range tests {
+		if s, _ := DeleteToUpdate(test[0], "deleted_at=current_timestamp"); s != test[1] {
+			t.Errorf("got %s, want %s", s, test[1])
+		}
+	}
+}
+
+func Test_statementTable(t *testing.T) {
+	tests := [][2]string{
+		{"select id,name from hstest where id=?", "hstest"},
+		{"SELECT count(*) FROM (select id from a) t", ""},
+		{"select a.id from `db`.`orders` a join items b on a.id=b.oid", "db.orders"},
+		{"insert into [dbo].[users](id,name) values(?,?)", "dbo.users"},
+		{"update \"Users\" set name=? where id in (select id from t)", "Users"},
+		{"delete from t where id=?", "t"},
+		{"with x as (select id from a) select * from x", "x"},
+		{"select 1", ""},
+	}
+	for _, test := range tests {
+		if s := StatementTable(test[0]); s != test[1] {
+			t.Errorf("got %s, want %s", s, test[1])
+		}
+	}
+}
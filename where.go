@@ -0,0 +1,59 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	. "github.com/donnie4w/gdao/base"
+)
+
+// And returns the conditions joined with and. A condition holding an or is put in parentheses,
+// and the arguments keep the order of the conditions.
+//
+// Example:
+//
+//	hs := dao.NewHstest()
+//	hs.Where(gdao.Or(gdao.And(hs.Age.GE(18), hs.Name.IsNotNull()), gdao.And(hs.Id.LT(10), gdao.Not(hs.Level.EQ(0)))))
+//	// where (age>=? and name is not null) or (id<? and not (level=?))
+func And[T any](wheres ...*Where[T]) *Where[T] {
+	return WhereJoin("and", wheres...)
+}
+
+// Or returns the conditions joined with or. A condition holding an and is put in parentheses,
+// and the arguments keep the order of the conditions.
+//
+// Example:
+//
+//	hs.Where(gdao.Or(hs.Id.EQ(1), hs.Name.IsNull()), hs.Age.GT(18))
+//	// where (id=? or name is null) and age>?
+func Or[T any](wheres ...*Where[T]) *Where[T] {
+	return WhereJoin("or", wheres...)
+}
+
+// Not returns the negation of the condition, not (where)
+func Not[T any](where *Where[T]) *Where[T] {
+	return WhereNot(where)
+}
+
+// Exists returns the condition exists (select ...) on the subquery, whose sql and arguments
+// are taken when Exists is called.
+//
+// Example:
+//
+//	order := dao.NewOrders()
+//	order.Where(order.Amount.GT(100))
+//	hs := dao.NewHstest()
+//	hs.Where(gdao.Exists[dao.Hstest](order.Subquery(order.Id)))
+//	// where exists (select id from orders where amount>?)
+func Exists[T any](q Subquery) *Where[T] {
+	return WhereExists[T](q)
+}
+
+// NotExists returns the condition not exists (select ...) on the subquery, see Exists
+func NotExists[T any](q Subquery) *Where[T] {
+	return WhereNotExists[T](q)
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2024, donnie <donnie4w@gmail.com>
+// All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+// github.com/donnie4w/gdao
+
+package gdao
+
+import (
+	"testing"
+)
+
+func Test_where(t *testing.T) {
+	hs := newHstest()
+	hs.Where(Or(And(hs.AGE.GE(18), hs.NAME.IsNotNull()), And(hs.ID.LT(10), Not(hs.VERSION.EQ(0)))))
+	where, args := hs.whereClause()
+	checkSql(t, where, args, " where (age>=? and name is not null) or (id<? and not (version=?))", 18, 10, 0)
+
+	hs = newHstest()
+	hs.Where(Or(hs.ID.EQ(1), hs.NAME.IsNull()), hs.AGE.GT(18))
+	where, args = hs.whereClause()
+	checkSql(t, where, args, " where (id=? or name is null) and age>?", 1, 18)
+}
+
+func Test_exists(t *testing.T) {
+	useTestDB(t, MYSQL, nil)
+	o := newHstest()
+	o.Where(o.AGE.GT(60))
+	hs := newHstest()
+	hs.Where(Exists[hstest](o.Subquery(o.ID)), NotExists[hstest](o.Subquery(o.NAME)), hs.ID.GT(1))
+	where, args := hs.whereClause()
+	checkSql(t, where, args, " where exists ( select id from hstest where age>?) and not exists ( select name from hstest where age>?) and id>?", 60, 60, 1)
+}